@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/project"
+)
+
+func buildCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "build",
+		Usage:     "build the targets of the project manifest",
+		ArgsUsage: "[target...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "project manifest file",
+				Value:   project.DefaultFile,
+			},
+			&cli.StringSliceFlag{
+				Name:  "var",
+				Usage: "variable in key=value, override the one in manifest, spec and config file",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			vars, err := parseVars(ctx.StringSlice("var"))
+			if err != nil {
+				return err
+			}
+			m, err := project.Load(ctx.String("project"))
+			if err != nil {
+				return err
+			}
+			return m.Build(vars, ctx.Args().Slice()...)
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/openapi"
+	"gopkg.in/yaml.v3"
+)
+
+func importOpenAPICommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import-openapi",
+		Usage:     "generate one feature per operation of the OpenAPI document",
+		ArgsUsage: "<openapi.yaml>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "module",
+				Usage: "name of the module (default: title of the API)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output spec file (default: stdout)",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 1 {
+				return eris.New("OpenAPI document is required, e.g. pst import-openapi -o api.yml openapi.yaml")
+			}
+			doc, err := openapi.Load(ctx.Args().First())
+			if err != nil {
+				return err
+			}
+			module := docb.Module{Name: ctx.String("module")}
+			if module.Name == "" {
+				module.Name = doc.Title()
+			}
+			for _, op := range doc.Operations() {
+				module.Features = append(module.Features, doc.Feature(op))
+			}
+
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "# generated by pst import-openapi from %s\n", doc.File)
+			encoder := yaml.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(docb.ProgSpec{Modules: []docb.Module{module}}); err != nil {
+				return eris.Wrap(err, "failed to marshal the features")
+			}
+
+			w, err := createOutput(ctx.String("output"))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return eris.Wrap(err, "failed to write the features")
+			}
+			return nil
+		},
+	}
+}
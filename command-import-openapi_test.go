@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+func TestImportOpenAPICommand(t *testing.T) {
+	dir := t.TempDir()
+	api, out := filepath.Join(dir, "openapi.yaml"), filepath.Join(dir, "api.yml")
+	content := `openapi: 3.0.0
+info: {title: User API}
+paths:
+  /users:
+    get:
+      operationId: listUsers
+      responses:
+        "200": {description: the users}
+    post:
+      summary: Create user
+      requestBody:
+        content:
+          application/json:
+            schema: {properties: {name: {type: string}}}
+      responses:
+        "201": {description: created}
+`
+	if err := os.WriteFile(api, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args   []string
+		module string
+	}{
+		{[]string{"-o", out, api}, "User API"},
+		{[]string{"--module", "Users", "-o", out, api}, "Users"},
+	}
+	for _, tt := range tests {
+		app := &cli.App{Commands: []*cli.Command{importOpenAPICommand()}}
+		if err := app.Run(append([]string{"pst", "import-openapi"}, tt.args...)); err != nil {
+			t.Fatal(err)
+		}
+		// the generated spec is loaded by pst
+		specs, err := docb.Load("", out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		module := specs[0].Data.Modules[0]
+		ids := []string{}
+		for _, feature := range module.Features {
+			ids = append(ids, feature.Id.(string)+" "+feature.Api.String())
+		}
+		if want := []string{"listUsers GET /users", "POST_users POST /users"}; module.Name != tt.module || !reflect.DeepEqual(ids, want) {
+			t.Errorf("got module %s with %v, want %s with %v", module.Name, ids, tt.module, want)
+		}
+	}
+
+	app := &cli.App{Commands: []*cli.Command{importOpenAPICommand()}}
+	if err := app.Run([]string{"pst", "import-openapi"}); err == nil {
+		t.Error("missing document should fail")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+func importResultsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "import-results",
+		Usage: "update the actual results of the tests by the workbook of the .xlsx output",
+		Flags: append(inputFlags(),
+			&cli.StringFlag{
+				Name:     "workbook",
+				Aliases:  []string{"x"},
+				Usage:    "workbook with the actual results filled in",
+				Required: true,
+			},
+		),
+		Action: func(ctx *cli.Context) error {
+			_, specs, err := loadInputs(ctx)
+			if err != nil {
+				return err
+			}
+			changed, err := docb.ImportActualResults(ctx.String("workbook"), specs)
+			for _, file := range changed {
+				logrus.Infof("updated %s", file)
+			}
+			if err != nil {
+				return err
+			}
+			if len(changed) == 0 {
+				logrus.Info("no actual result is changed")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/scaffold"
+)
+
+func initCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "create the manifest, config, template, sample spec and images directory",
+		ArgsUsage: "[directory]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "overwrite the existing files",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			dir := "."
+			if ctx.Args().Present() {
+				dir = ctx.Args().First()
+			}
+			return scaffold.Init(dir, ctx.Bool("force"))
+		},
+	}
+}
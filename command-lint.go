@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/config"
+	"github.com/zrs01/pst/internal/lint"
+)
+
+func lintCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "check the specs with the lint rules",
+		Flags: append(inputFlags(),
+			&cli.StringFlag{
+				Name:  "output-format",
+				Usage: "output format: text, json or sarif",
+				Value: lint.FormatText,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output file (default: stdout)",
+			},
+			&cli.BoolFlag{
+				Name:  "rules",
+				Usage: "list the rules and their default severities",
+			},
+		),
+		Action: func(ctx *cli.Context) error {
+			if ctx.Bool("rules") {
+				for _, rule := range lint.Rules() {
+					fmt.Printf("%-22s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+				}
+				return nil
+			}
+
+			cfile, specs, err := loadInputs(ctx)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.NewConfig(cfile)
+			if err != nil {
+				return eris.Wrapf(err, "failed to load the configuration file %s", cfile)
+			}
+			issues, err := lint.Check(specs, cfg)
+			if err != nil {
+				return err
+			}
+
+			w, err := createOutput(ctx.String("output"))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			if err := lint.Write(w, ctx.String("output-format"), issues); err != nil {
+				return err
+			}
+			if n := lint.CountErrors(issues); n > 0 {
+				return eris.Errorf("%d errors found", n)
+			}
+			return nil
+		},
+	}
+}
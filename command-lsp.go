@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/lsp"
+)
+
+func lspCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "lsp",
+		Usage: "language server of the spec files on stdin and stdout for the editors",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "config file",
+			},
+			&cli.StringFlag{
+				Name:    "base-dir",
+				Aliases: []string{"b"},
+				Usage:   "base directory of the images (default: directory of the input file)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "var",
+				Usage: "variable in key=value, override the one in spec and config file",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			vars, err := parseVars(ctx.StringSlice("var"))
+			if err != nil {
+				return err
+			}
+			return lsp.Serve(os.Stdin, os.Stdout, lsp.Args{
+				ConfigFile: ctx.String("config"),
+				Options:    docb.Options{Vars: vars, BaseDir: ctx.String("base-dir")},
+			})
+		},
+	}
+}
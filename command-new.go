@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/project"
+	"github.com/zrs01/pst/internal/scaffold"
+)
+
+func newCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "new",
+		Usage: "add a stub to the spec",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "feature",
+				Usage: "append a feature stub to the module",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "module",
+						Usage:    "name of the module",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "id",
+						Usage: "feature ID (default: next ID of the module, e.g. UF011A -> UF012A)",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "feature name",
+					},
+					&cli.StringFlag{
+						Name:    "input",
+						Aliases: []string{"i"},
+						Usage:   "input files searched for the module (default: inputs of the project manifest, or the current directory)",
+					},
+					&cli.StringFlag{
+						Name:    "project",
+						Aliases: []string{"p"},
+						Usage:   "project manifest file",
+						Value:   project.DefaultFile,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					ifile := ctx.String("input")
+					if ifile == "" {
+						m, err := loadManifest(ctx.String("project"))
+						if err != nil {
+							return err
+						}
+						if ifile = "."; m != nil && m.InputFiles() != "" {
+							ifile = m.InputFiles()
+						}
+					}
+					_, _, err := scaffold.NewFeature(ifile, scaffold.FeatureArgs{
+						Module: ctx.String("module"),
+						Id:     ctx.String("id"),
+						Name:   ctx.String("name"),
+					})
+					return err
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/preview"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "preview the spec as HTML in browser, reload on change",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "address to listen",
+				Value: "localhost:8080",
+			},
+		}, specFlags()...),
+		Action: func(ctx *cli.Context) error {
+			args, err := newSpecArgs(ctx)
+			if err != nil {
+				return err
+			}
+			if args.ifile == "" {
+				return eris.New(`required flag "input" is not set`)
+			}
+			return preview.Serve(ctx.String("addr"), preview.Args{
+				ConfigFile:   args.cfile,
+				InputFile:    args.ifile,
+				DocumentFile: args.dfile,
+				Options:      args.options,
+			})
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+func statsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "report the statistics and completeness of the specs by module",
+		Flags: append(inputFlags(),
+			&cli.StringFlag{
+				Name:  "output-format",
+				Usage: "output format: table, json or csv",
+				Value: "table",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "output file (default: stdout)",
+			},
+		),
+		Action: func(ctx *cli.Context) error {
+			_, specs, err := loadInputs(ctx)
+			if err != nil {
+				return err
+			}
+			data := []*docb.ProgSpec{}
+			for _, spec := range specs {
+				if !spec.Appendix {
+					data = append(data, spec.Data)
+				}
+			}
+			stats := docb.Stats(data)
+			total := docb.TotalStats(stats)
+
+			w, err := createOutput(ctx.String("output"))
+			if err != nil {
+				return err
+			}
+			defer w.Close()
+			switch format := ctx.String("output-format"); format {
+			case "table":
+				return writeStatsTable(w, append(stats, total))
+			case "json":
+				encoder := json.NewEncoder(w)
+				encoder.SetIndent("", "  ")
+				return eris.Wrap(encoder.Encode(map[string]interface{}{"modules": stats, "total": total}), "failed to write the statistics")
+			case "csv":
+				return writeStatsCsv(w, append(stats, total))
+			default:
+				return eris.Errorf("unknown output format %s, must be one of table, json or csv", format)
+			}
+		},
+	}
+}
+
+func writeStatsTable(w io.Writer, stats []docb.ModuleStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rows := [][]string{docb.StatsHeaders}
+	for _, s := range stats {
+		rows = append(rows, s.Record())
+	}
+	for _, row := range rows {
+		for _, col := range row {
+			fmt.Fprint(tw, col, "\t")
+		}
+		fmt.Fprintln(tw)
+	}
+	return eris.Wrap(tw.Flush(), "failed to write the statistics")
+}
+
+func writeStatsCsv(w io.Writer, stats []docb.ModuleStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write(docb.StatsHeaders)
+	for _, s := range stats {
+		record := s.Record()
+		record[len(record)-1] = fmt.Sprintf("%.1f", s.Completeness)
+		cw.Write(record)
+	}
+	cw.Flush()
+	return eris.Wrap(cw.Error(), "failed to write the statistics")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/project"
+	"github.com/zrs01/pst/internal/watch"
+)
+
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "watch",
+		Usage:     "rebuild on change of the input, included, image, template and config files",
+		ArgsUsage: "[target...]",
+		Description: "Build from the input flags if provided, otherwise the targets of the project manifest.\n" +
+			"Only the targets (or the input files if split by file) affected by the change are rebuilt.",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "project manifest file",
+				Value:   project.DefaultFile,
+			},
+			&cli.DurationFlag{
+				Name:  "debounce",
+				Usage: "quiet period after the last change before rebuilding",
+				Value: watch.DefaultDebounce,
+			},
+		}, specFlags()...),
+		Action: func(ctx *cli.Context) error {
+			args, err := newSpecArgs(ctx)
+			if err != nil {
+				return err
+			}
+
+			if args.ifile != "" {
+				if args.ofile == "" {
+					return eris.New(`required flag "output" is not set`)
+				}
+				return watch.Run(func() ([]watch.Unit, error) {
+					return watch.SpecUnits(args.ofile, args.cfile, args.ifile, args.ofile, args.dfile, args.options)
+				}, ctx.Duration("debounce"))
+			}
+
+			// reload the manifest on every change since the manifest itself is watched
+			pfile := ctx.String("project")
+			return watch.Run(func() ([]watch.Unit, error) {
+				m, err := project.Load(pfile)
+				if err != nil {
+					return nil, err
+				}
+				return m.Units(args.options.Vars, ctx.Args().Slice()...)
+			}, ctx.Duration("debounce"), pfile)
+		},
+	}
+}
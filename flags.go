@@ -0,0 +1,240 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/urfave/cli/v2"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/project"
+)
+
+// specArgs are the arguments of docb.Build from the command line
+type specArgs struct {
+	cfile, ifile, ofile, dfile string
+	options                    docb.Options
+}
+
+// specFlags returns the flags to build the spec, shared by the commands building from the command line
+func specFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "config",
+			Aliases:  []string{"c"},
+			Usage:    "config file",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "input",
+			Aliases:  []string{"i"},
+			Usage:    "input file, directory or wildcard (e.g. specs/**/*.yml), - for stdin",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "output",
+			Aliases:  []string{"o"},
+			Usage:    "output file, - for stdout, output directory if split",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "document",
+			Aliases:  []string{"m"},
+			Usage:    "existing .docx file",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Aliases:  []string{"f"},
+			Usage:    "input format: yaml, json or toml (default: by file extension)",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "base-dir",
+			Aliases:  []string{"b"},
+			Usage:    "base directory of the images (default: directory of the input file)",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "split",
+			Usage:    "write one document per input file or module: file, module",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "name",
+			Usage:    "name pattern of the split document, e.g. {module}-{version}.docx (default: {name}.docx or {module}.docx)",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "var",
+			Usage:    "variable in key=value, override the one in spec and config file",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "summary",
+			Usage:    "insert the statistics page at the front of the document",
+			Required: false,
+		},
+	}
+}
+
+func newSpecArgs(ctx *cli.Context) (*specArgs, error) {
+	vars, err := parseVars(ctx.StringSlice("var"))
+	if err != nil {
+		return nil, err
+	}
+	return &specArgs{
+		cfile: ctx.String("config"),
+		ifile: ctx.String("input"),
+		ofile: ctx.String("output"),
+		dfile: ctx.String("document"),
+		options: docb.Options{
+			Vars:    vars,
+			Format:  ctx.String("format"),
+			BaseDir: ctx.String("base-dir"),
+			Split:   ctx.String("split"),
+			Pattern: ctx.String("name"),
+			Summary: ctx.Bool("summary"),
+		},
+	}, nil
+}
+
+// parseVars converts the key=value pairs to map
+func parseVars(values []string) (map[string]string, error) {
+	vars := map[string]string{}
+	last := ""
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 {
+			// the slice flag splits the value by comma, join it back to the previous variable
+			if last == "" {
+				return nil, eris.Errorf("invalid variable %s, expect key=value", value)
+			}
+			vars[last] += "," + value
+			continue
+		}
+		last = strings.TrimSpace(kv[0])
+		if last == "" {
+			return nil, eris.Errorf("invalid variable %s, expect key=value", value)
+		}
+		vars[last] = kv[1]
+	}
+	return vars, nil
+}
+
+// loadManifest loads the project manifest if it exists, nil otherwise
+func loadManifest(file string) (*project.Manifest, error) {
+	if _, err := os.Stat(file); err != nil {
+		return nil, nil
+	}
+	return project.Load(file)
+}
+
+// inputFlags returns the flags to load the specs, the project manifest is used if the input is not set
+func inputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "config file (default: config of the project manifest)",
+		},
+		&cli.StringFlag{
+			Name:    "input",
+			Aliases: []string{"i"},
+			Usage:   "input file, directory or wildcard (default: inputs of the project manifest)",
+		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "input format: yaml, json or toml (default: by file extension)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "var",
+			Usage: "variable in key=value, override the one in manifest, spec and config file",
+		},
+		&cli.StringFlag{
+			Name:    "project",
+			Aliases: []string{"p"},
+			Usage:   "project manifest file",
+			Value:   project.DefaultFile,
+		},
+		&cli.StringFlag{
+			Name:    "target",
+			Aliases: []string{"t"},
+			Usage:   "use the config, inputs and variables of the target in the project manifest",
+		},
+	}
+}
+
+// loadInputs loads the specs by the input flags, returns the config file as well
+func loadInputs(ctx *cli.Context) (string, []docb.Spec, error) {
+	vars, err := parseVars(ctx.StringSlice("var"))
+	if err != nil {
+		return "", nil, err
+	}
+	cfile, ifile := ctx.String("config"), ctx.String("input")
+	options := docb.Options{Vars: vars, Format: ctx.String("format")}
+	if target := ctx.String("target"); target != "" {
+		m, err := project.Load(ctx.String("project"))
+		if err != nil {
+			return "", nil, err
+		}
+		tcfile, tifile, topts, err := m.SpecArgs(target, vars)
+		if err != nil {
+			return "", nil, err
+		}
+		options.Vars = topts.Vars
+		options.BaseDir = topts.BaseDir
+		if options.Format == "" {
+			options.Format = topts.Format
+		}
+		if cfile == "" {
+			cfile = tcfile
+		}
+		if ifile == "" {
+			ifile = tifile
+		}
+	} else if ifile == "" {
+		m, err := loadManifest(ctx.String("project"))
+		if err != nil {
+			return "", nil, err
+		}
+		if m == nil || m.InputFiles() == "" {
+			return "", nil, eris.New(`required flag "input" is not set`)
+		}
+		ifile = m.InputFiles()
+		if cfile == "" {
+			cfile = m.ConfigFile()
+		}
+		for k, v := range m.Vars {
+			if _, ok := vars[k]; !ok {
+				vars[k] = v
+			}
+		}
+	}
+
+	specs, err := docb.Load(cfile, ifile, options)
+	if err != nil {
+		return "", nil, err
+	}
+	return cfile, specs, nil
+}
+
+// createOutput creates the output file, stdout if the file is blank
+func createOutput(file string) (io.WriteCloser, error) {
+	if file == "" || file == docb.StdStream {
+		return nopCloser{os.Stdout}, nil
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to create the output file %s", file)
+	}
+	return f, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
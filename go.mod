@@ -4,20 +4,21 @@ go 1.18
 
 require (
 	baliance.com/gooxml v1.0.1
+	github.com/BurntSushi/toml v1.1.0
+	github.com/fsnotify/fsnotify v1.5.4
 	github.com/jinzhu/configor v1.2.1
 	github.com/rotisserie/eris v0.5.4
 	github.com/shomali11/util v0.0.0-20200329021417-91c54758c87b
 	github.com/sirupsen/logrus v1.8.1
 	github.com/thoas/go-funk v0.9.2
 	github.com/urfave/cli/v2 v2.10.3
-	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/BurntSushi/toml v1.1.0 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
 	golang.org/x/sys v0.0.0-20220627191245-f75cf1eec38b // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
@@ -1,24 +1,43 @@
 package config
 
 import (
+	"path/filepath"
+
 	"github.com/jinzhu/configor"
 	"github.com/sirupsen/logrus"
 )
 
 type Config struct {
-	FontFamily string `yaml:"fontfamily,omitempty"`
-	FontSize   int    `yaml:"fontsize,omitempty"`
+	FontFamily string            `yaml:"fontfamily,omitempty"`
+	FontSize   int               `yaml:"fontsize,omitempty"`
+	CodeFont   string            `yaml:"codefont,omitempty"` // font of the `code` in the text
+	Lists      map[string]string `yaml:"lists,omitempty"`    // field -> numbering of the lines: none, bullet or decimal
+	PageRefs   bool              `yaml:"pagerefs,omitempty"` // add the page number after the linked IDs
+	Vars       map[string]string `yaml:"vars,omitempty"`
+	Lint       Lint              `yaml:"lint,omitempty"`
 	Logging    struct {
 		Level string
 	}
 }
 
+// Lint is the settings of the lint rules
+type Lint struct {
+	IdPatterns   map[string]string `yaml:"idpatterns,omitempty"`   // module name -> regular expression of the feature ID, * for all modules
+	Rules        map[string]string `yaml:"rules,omitempty"`        // rule name -> severity: error, warning, info or off
+	DescMinWords int               `yaml:"descminwords,omitempty"` // minimum words of the feature description
+	Placeholders []string          `yaml:"placeholders,omitempty"` // words not allowed by the no-placeholder rule
+	OpenAPI      string            `yaml:"openapi,omitempty"`      // OpenAPI document checked by the api-drift rule, relative to the config file
+}
+
 // NewConfig creates new instance of the configuration from the file
 func NewConfig(f string) (*Config, error) {
 	cfg := &Config{}
 	cfg.FontFamily = "Arial"
 	cfg.FontSize = 10
+	cfg.CodeFont = "Courier New"
 	cfg.Logging.Level = "INFO"
+	cfg.Lint.DescMinWords = 5
+	cfg.Lint.Placeholders = []string{"TBD", "Nil"}
 
 	if f != "" {
 		if err := cfg.load(f); err != nil {
@@ -32,6 +51,9 @@ func (c *Config) load(cf string) error {
 	if err := configor.Load(c, cf); err != nil {
 		return err
 	}
+	if c.Lint.OpenAPI != "" && !filepath.IsAbs(c.Lint.OpenAPI) {
+		c.Lint.OpenAPI = filepath.Join(filepath.Dir(cf), c.Lint.OpenAPI)
+	}
 	logrus.SetLevel(c.getLevel())
 	return nil
 }
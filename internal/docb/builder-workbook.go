@@ -0,0 +1,145 @@
+package docb
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/spreadsheet"
+	"github.com/rotisserie/eris"
+)
+
+// sheets of the workbook, the tests sheet is read back by ImportActualResults
+const (
+	SheetTests      = "Tests"
+	SheetParameters = "Parameters"
+	SheetResources  = "Resources"
+)
+
+// columns of the tests sheet
+const (
+	ColumnFeatureId = "Feature ID"
+	ColumnTestNo    = "Test #"
+	ColumnActual    = "Actual Result"
+	ColumnResult    = "Result"
+)
+
+var testResults = []string{"Pass", "Fail"}
+
+// isWorkbook tells whether the output is the spreadsheet of the test records instead of the document
+func isWorkbook(file string) bool {
+	return strings.EqualFold(filepath.Ext(file), ".xlsx")
+}
+
+// workbookSheet is the sheet of the workbook being written
+type workbookSheet struct {
+	sheet  spreadsheet.Sheet
+	bold   spreadsheet.CellStyle
+	widths []float64
+	rows   int
+}
+
+func newWorkbookSheet(wb *spreadsheet.Workbook, name string, headers ...string) *workbookSheet {
+	bold := wb.StyleSheet.AddCellStyle()
+	font := wb.StyleSheet.AddFont()
+	font.SetBold(true)
+	bold.SetFont(font)
+
+	s := &workbookSheet{sheet: wb.AddSheet(), bold: bold, widths: make([]float64, len(headers))}
+	s.sheet.SetName(name)
+	row := s.sheet.AddRow()
+	for i, header := range headers {
+		cell := row.AddCell()
+		cell.SetString(header)
+		cell.SetStyle(bold)
+		s.fit(i, header)
+	}
+	return s
+}
+
+func (s *workbookSheet) addRow(values ...interface{}) {
+	row := s.sheet.AddRow()
+	for i, value := range values {
+		text := ""
+		if value != nil {
+			text = strings.Join(ToStrArray(value), "\n")
+		}
+		row.AddCell().SetString(text)
+		s.fit(i, text)
+	}
+	s.rows++
+}
+
+// fit widens the column to the longest line, up to 60 characters
+func (s *workbookSheet) fit(col int, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if w := float64(len(line) + 2); w > s.widths[col] {
+			s.widths[col] = w
+			if s.widths[col] > 60 {
+				s.widths[col] = 60
+			}
+		}
+	}
+}
+
+// finish freezes the header, adds the filters and sets the column widths
+func (s *workbookSheet) finish() {
+	last := columnName(len(s.widths) - 1)
+	s.sheet.SetFrozen(true, false)
+	s.sheet.SetAutoFilter(fmt.Sprintf("A1:%s%d", last, s.rows+1))
+	for i, w := range s.widths {
+		s.sheet.Column(uint32(i + 1)).SetWidth(measurement.Distance(w * measurement.Character))
+	}
+}
+
+// columnName returns the column letters of the zero-based index, e.g. 0 -> A, 26 -> AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// writeWorkbook writes the tests, parameters and resources of the specs into the spreadsheet
+func (b *Builder) writeWorkbook(ofile string, specs ...*loadedSpec) error {
+	wb := spreadsheet.New()
+	tests := newWorkbookSheet(wb, SheetTests, ColumnFeatureId, "Feature", ColumnTestNo, "Test Description", "Expected Result", ColumnActual, ColumnResult)
+	params := newWorkbookSheet(wb, SheetParameters, ColumnFeatureId, "Feature", "Input #", "Fields", "Data Items", "I/O", "Processing Remarks")
+	resources := newWorkbookSheet(wb, SheetResources, ColumnFeatureId, "Feature", "Table/File", "Usage")
+
+	for _, spec := range specs {
+		for _, module := range spec.data.Modules {
+			for _, feature := range module.Features {
+				id := strings.Join(ToStrArray(feature.Id), " ")
+				for i, test := range feature.Tests {
+					tests.addRow(id, feature.Name, fmt.Sprint(i+1), test.Desc, test.Expect, test.Actual, test.Result)
+				}
+				for i, param := range feature.Parameters {
+					params.addRow(id, feature.Name, fmt.Sprint(i+1), param.Field, param.Data, param.IO, param.Remarks)
+				}
+				for _, res := range feature.Resources {
+					resources.addRow(id, feature.Name, res.Name, res.Usage)
+				}
+			}
+		}
+	}
+
+	// pass or fail of the result column
+	if tests.rows > 0 {
+		col := columnName(len(tests.widths) - 1)
+		dv := tests.sheet.AddDataValidation()
+		dv.SetRange(fmt.Sprintf("%s2:%s%d", col, col, tests.rows+1))
+		dv.SetList().SetValues(testResults)
+	}
+	for _, s := range []*workbookSheet{tests, params, resources} {
+		s.finish()
+	}
+
+	if err := wb.SaveToFile(ofile); err != nil {
+		return eris.Wrapf(err, "failed to save the file %s", ofile)
+	}
+	return nil
+}
@@ -5,29 +5,46 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
-	"sort"
 	"strings"
 
 	"baliance.com/gooxml/color"
-	"baliance.com/gooxml/document"
 	"baliance.com/gooxml/measurement"
 	"baliance.com/gooxml/schema/soo/wml"
 	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xconditions"
 	"github.com/shomali11/util/xstrings"
 	"github.com/thoas/go-funk"
 	"github.com/zrs01/pst/internal/config"
-	"gopkg.in/yaml.v2"
+	"github.com/zrs01/pst/pkg/docx"
 )
 
 type Builder struct {
-	cfile  string // config file name
-	ifile  string // input file name
-	ofile  string // output file name
-	dfile  string // .docx file name
-	config *config.Config
+	cfile      string // config file name
+	ifile      string // input file name
+	ofile      string // output file name
+	dfile      string // .docx file name
+	config     *config.Config
+	options    Options
+	appendices []*loadedSpec // appended to every document
+	loaded     []string      // input and included files read
 }
 
-func Build(cfile, ifile, ofile string, tfile string) error {
+// Options are the optional settings of the build
+type Options struct {
+	Vars     map[string]string // override the variables defined in the spec and config file
+	Format   string            // format of the input file, detect by extension if blank
+	BaseDir  string            // directory to resolve the images and includes of stdin, default is the directory of the input file
+	Split    string            // write one document per input file or module into the output directory
+	Pattern  string            // name of the split document, e.g. {module}-{version}.docx
+	Appendix string            // appendix input files appended to every document, same format as the input file
+	Overlay  map[string][]byte // content used instead of reading the file, e.g. unsaved buffer of the editor
+	Summary  bool              // insert the statistics page at the front of the document, see Stats
+}
+
+// StdStream is the file name of stdin for input and stdout for output
+const StdStream = "-"
+
+func Build(cfile, ifile, ofile string, tfile string, opts ...Options) error {
 	ncfg, err := config.NewConfig(cfile)
 	if err != nil {
 		return eris.Wrapf(err, "failed to load the configuration file %s", cfile)
@@ -35,64 +52,212 @@ func Build(cfile, ifile, ofile string, tfile string) error {
 
 	// ifilePath = path.Dir(ifile)
 	b := &Builder{ifile: ifile, ofile: ofile, dfile: tfile, config: ncfg}
+	if len(opts) > 0 {
+		b.options = opts[0]
+	}
 	return b.construct()
 }
 
+// Spec is the loaded input file with the variables substituted and the image paths resolved
+type Spec struct {
+	File     string
+	Appendix bool
+	Data     *ProgSpec
+}
+
+// Load loads the input files and appendices with the same arguments as Build without writing the document
+func Load(cfile, ifile string, opts ...Options) ([]Spec, error) {
+	ncfg, err := config.NewConfig(cfile)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to load the configuration file %s", cfile)
+	}
+	b := &Builder{ifile: ifile, config: ncfg}
+	if len(opts) > 0 {
+		b.options = opts[0]
+	}
+	specs, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	result := []Spec{}
+	for _, spec := range append(specs, b.appendices...) {
+		result = append(result, Spec{File: spec.file, Appendix: spec.appendix, Data: spec.data})
+	}
+	return result, nil
+}
+
 func (b *Builder) construct() error {
-	docb, err := NewDocumentBuilder(b.dfile, Configuration{
-		FontFamily: b.config.FontFamily,
-		FontSize:   b.config.FontSize,
-		ImagePath:  filepath.Dir(b.ifile),
-	})
+	for field, name := range b.config.Lists {
+		if _, ok := docx.ParseListKind(name); !ok {
+			return eris.Errorf("invalid list %s of %s in the configuration, must be none, bullet or decimal", name, field)
+		}
+	}
+	specs, err := b.load()
 	if err != nil {
-		return eris.Wrap(err, "failed to create document builder")
+		return err
 	}
 
+	switch b.options.Split {
+	case "":
+		return b.write(b.ofile, specs...)
+	case SplitFile, SplitModule:
+		return b.writeSplit(specs)
+	}
+	return eris.Errorf("unknown split mode %s", b.options.Split)
+}
+
+// load loads the input files and the appendices
+func (b *Builder) load() ([]*loadedSpec, error) {
 	// resolve wildcard
 	files, err := b.resolveInputFile(b.ifile)
 	if err != nil {
-		return eris.Wrap(err, "failed to resolve the source file")
+		return nil, eris.Wrap(err, "failed to resolve the source file")
 	}
+	specs := []*loadedSpec{}
 	for _, file := range *files {
-		data, err := b.loadData(file)
+		spec, err := b.loadSpec(file)
 		if err != nil {
-			return eris.Wrap(err, "failed to load the file")
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	if xstrings.IsNotBlank(b.options.Appendix) {
+		files, err := b.resolveInputFile(b.options.Appendix)
+		if err != nil {
+			return nil, eris.Wrap(err, "failed to resolve the appendix file")
+		}
+		for _, file := range *files {
+			spec, err := b.loadSpec(file)
+			if err != nil {
+				return nil, err
+			}
+			spec.appendix = true
+			b.appendices = append(b.appendices, spec)
+		}
+	}
+	return specs, nil
+}
+
+// loadSpec loads the file and substitutes the variables
+func (b *Builder) loadSpec(file string) (*loadedSpec, error) {
+	data, err := b.loadData(file)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to load the file")
+	}
+	vars := newVariables(b.config.Vars, data.Vars, b.options.Vars)
+	for i := range data.Modules {
+		if err := vars.apply(&data.Modules[i]); err != nil {
+			return nil, eris.Wrapf(err, "failed to substitute the variables in %s", file)
 		}
+	}
+	b.resolveImagePath(file, data)
+	return &loadedSpec{file: file, data: data, vars: vars}, nil
+}
+
+// write constructs the specs into one document
+func (b *Builder) write(ofile string, specs ...*loadedSpec) error {
+	if isWorkbook(ofile) {
+		return b.writeWorkbook(ofile, specs...)
+	}
+
+	all := append(specs, b.appendices...)
+	data := []*ProgSpec{}
+	for _, spec := range all {
+		data = append(data, spec.data)
+	}
+	index := NewIdIndex(data)
+	docb, err := docx.NewDocumentBuilder(b.dfile, docx.Configuration{
+		FontFamily:     b.config.FontFamily,
+		FontSize:       b.config.FontSize,
+		CodeFontFamily: b.config.CodeFont,
+		Bookmarks:      index.Bookmarks(),
+		PageRefs:       b.config.PageRefs,
+	})
+	if err != nil {
+		return eris.Wrap(err, "failed to create document builder")
+	}
+
+	styles := addTableStyles(docb)
+	if b.options.Summary {
+		b.writeSummary(docb, styles, specs)
+	}
 
+	marked := map[string]bool{} // bookmarks added, the first feature is the target if the ID is duplicated
+	for _, spec := range all {
 		// header
-		docb.AddParagraph(func(p *ParagraphBuilder) {
-			p.SetStyle("Heading1").SetText("PROGRAM DESCRIPTON")
+		docb.AddParagraph(func(p *docx.ParagraphBuilder) {
+			p.SetStyle("Heading1").SetText(xconditions.IfThenElse(spec.appendix, "APPENDIX", "PROGRAM DESCRIPTON"))
 		})
 
-		for _, module := range data.Modules {
-			docb.AddParagraph(func(p *ParagraphBuilder) {
+		for _, module := range spec.data.Modules {
+			docb.AddParagraph(func(p *docx.ParagraphBuilder) {
 				p.SetStyle("Heading2").SetText(module.Name)
 			})
 			for _, feature := range module.Features {
 				docb.AddParagraph().
-					AddParagraph(func(p *ParagraphBuilder) {
+					AddParagraph(func(p *docx.ParagraphBuilder) {
 						p.SetStyle("Heading3").SetText(feature.Name)
+						for _, id := range ToStrArray(feature.Id) {
+							if name := index.FeatureBookmark(id); !marked[name] {
+								p.AddBookmark(name)
+								marked[name] = true
+							}
+						}
 					})
-				if err := b.constructFeature(docb, &feature); err != nil {
+				if err := b.constructFeature(docb, styles, &feature, index, marked); err != nil {
 					return eris.Wrap(err, "failed to build details")
 				}
 			}
-			docb.AddParagraph(func(p *ParagraphBuilder) {
+			docb.AddParagraph(func(p *docx.ParagraphBuilder) {
 				p.SetPageBreak()
 			})
 		}
-		docb.Build()
+	}
+	docb.Build()
 
+	if ofile == StdStream {
+		if err := docb.Document.Save(os.Stdout); err != nil {
+			return eris.Wrap(err, "failed to write the document to stdout")
+		}
+		return nil
+	}
+	if err := docb.Document.SaveToFile(ofile); err != nil {
+		return eris.Wrapf(err, "failed to save the file %s", ofile)
 	}
-	docb.Document.SaveToFile(b.ofile)
 	return nil
 }
 
-func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) error {
-	c1 := color.FromHex("ced4da") // gray
-	c2 := color.FromHex("e9ecef") // light gray
+// baseDir returns the directory to resolve the relative paths of the input file
+func (b *Builder) baseDir(file string) string {
+	if xstrings.IsNotBlank(b.options.BaseDir) {
+		return b.options.BaseDir
+	}
+	if file == StdStream {
+		return "."
+	}
+	return filepath.Dir(file)
+}
+
+// resolveImagePath makes the image relative to its input file since the files may be in different directories
+func (b *Builder) resolveImagePath(file string, data *ProgSpec) {
+	dir := b.baseDir(file)
+	for i := range data.Modules {
+		for j := range data.Modules[i].Features {
+			screens := data.Modules[i].Features[j].Screens
+			for k := range screens {
+				if xstrings.IsNotBlank(screens[k].Image.File) && !filepath.IsAbs(screens[k].Image.File) {
+					screens[k].Image.File = filepath.Join(dir, screens[k].Image.File)
+				}
+			}
+		}
+	}
+}
+
+// constructFeature writes the tables of the feature, index names the bookmarks and marked is the bookmarks added already
+func (b *Builder) constructFeature(docb *docx.DocumentBuilder, styles tableStyles, feature *Feature, index *IdIndex, marked map[string]bool) error {
+	header := styles.header
+	subheader := styles.subheader
 	wd := float64(20)
-	nd := docb.Document.Numbering.Definitions()[0]
 	bs := wml.ST_BorderSingle
 	bc := color.Auto
 	bt := measurement.Distance(0.5 * measurement.Point)
@@ -103,16 +268,20 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 		bold         bool
 		colspan      int
 		widthPercent float64
-		numbering    document.NumberingDefinition
+		list         docx.ListKind
 		alignment    wml.ST_Jc
 		allowEmpty   bool
+		noLinks      bool
+		code         bool // the text is the code as well, see CodeOf
 	}
 	type xrow struct {
 		cols     []xcol
-		bgColor  color.Color
+		style    string // style of the cells, the labels and the values by default
 		hasValue bool
+		header   bool // repeated on each page with the rows above
 	}
-	var createTable = func(newParagraph bool, rf func() []xrow) {
+	// widths is the column grid of the table in percent
+	var createTable = func(newParagraph bool, rf func() []xrow, widths ...float64) {
 		rows := rf()
 		isContentBlank := true
 		for i := 0; i < len(rows); i++ {
@@ -127,8 +296,19 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 			if newParagraph {
 				docb.AddParagraph()
 			}
-			docb.AddTable(func(tb *TableBuilder) {
-				tb.SetWidthPercent(100).SetBorders(func(b *Borders) { b.SetBorderAll(bs, bc, bt) })
+			docb.AddTable(func(tb *docx.TableBuilder) {
+				tb.SetWidthPercent(100).SetBorders(func(b *docx.Borders) { b.SetBorderAll(bs, bc, bt) })
+				if len(widths) > 0 {
+					tb.SetColumnWidths(widths...)
+				}
+				headers := 0
+				for headers < len(rows) && rows[headers].header {
+					headers++
+				}
+				if headers > 0 {
+					// the records are kept on one page under the repeated headers
+					tb.SetHeaderRows(headers).SetCantSplit()
+				}
 				for _, row := range rows {
 					// check if row contains value
 					isRowHasSomeValue := true
@@ -145,16 +325,26 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 					}
 
 					if (row.hasValue && isRowHasValue) || (!row.hasValue && isRowHasSomeValue) {
-						tb.AddRow(func(rb *RowBuilder) {
+						tb.AddRow(func(rb *docx.RowBuilder) {
 							for _, col := range row.cols {
 								if !b.isValueBlank(col.value) || col.allowEmpty {
-									rb.AddCell(func(cb *CellBuilder) {
-										cb.SetText(col.value)
-										if (row.bgColor != color.Color{}) {
-											cb.SetBackgroundColor(row.bgColor)
+									rb.AddCell(func(cb *docx.CellBuilder) {
+										if code, ok := CodeOf(col.value, col.code); ok {
+											cb.SetCode(code.Text, func(cp *docx.CodeProperty) { cp.SetLanguage(code.Lang).SetLineNumbers(code.Numbers) })
+										} else {
+											cb.SetText(col.value)
 										}
-										if col.bold {
-											cb.SetBold()
+										switch {
+										case row.style != "":
+											cb.SetStyle(row.style)
+											if !col.bold {
+												// e.g. the ID in the header row
+												cb.SetNotBold()
+											}
+										case col.bold:
+											cb.SetStyle(styles.label)
+										default:
+											cb.SetStyle(styles.body)
 										}
 										if col.colspan > 0 {
 											cb.SetColspan(col.colspan)
@@ -162,12 +352,15 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 										if col.widthPercent > 0 {
 											cb.SetWidthPercent(col.widthPercent)
 										}
-										if (col.numbering != document.NumberingDefinition{}) {
-											cb.SetBullet(&col.numbering)
+										if col.list != docx.ListNone {
+											cb.SetList(col.list)
 										}
 										if col.alignment != wml.ST_JcUnset {
 											cb.SetAlignment(col.alignment)
 										}
+										if col.noLinks {
+											cb.DisableLinks()
+										}
 									})
 								}
 							}
@@ -182,19 +375,20 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 	/* --------------------------------- PROGRAM -------------------------------- */
 	createTable(false, func() []xrow {
 		result := []xrow{
-			{cols: []xcol{{value: "Program ID", bold: true, widthPercent: wd}, {value: feature.Id}}, bgColor: c1},
+			{cols: []xcol{{value: "Program ID", bold: true, widthPercent: wd}, {value: feature.Id, noLinks: true}}, style: header},
 			{cols: []xcol{{value: "Mode", bold: true}, {value: feature.Mode}}, hasValue: true},
+			{cols: []xcol{{value: "API", bold: true}, {value: feature.Api.String()}}, hasValue: true},
 			{cols: []xcol{{value: "Program Name", bold: true}, {value: feature.Name}}, hasValue: true},
-			{cols: []xcol{{value: "Description", bold: true}, {value: feature.Desc}}, hasValue: true},
+			{cols: []xcol{{value: "Description", bold: true}, {value: feature.Desc, list: b.listKind("desc")}}, hasValue: true},
 
-			{cols: []xcol{{value: "Program Environment:", bold: true, colspan: 2}}, bgColor: c1},
+			{cols: []xcol{{value: "Program Environment:", bold: true, colspan: 2}}, style: header},
 			{cols: []xcol{{value: "Program Source", bold: true}, {value: feature.Mode}}, hasValue: true},
 			{cols: []xcol{{value: "Language", bold: true}, {value: feature.Env.Languages}}, hasValue: true},
 		}
 		if !b.isValueBlank(feature.Amendment) {
 			result = append(result,
-				xrow{cols: []xcol{{value: "Amendment History:", bold: true, colspan: 2}}, bgColor: c1},
-				xrow{cols: []xcol{{value: feature.Amendment, colspan: 2}}, hasValue: true},
+				xrow{cols: []xcol{{value: "Amendment History:", bold: true, colspan: 2}}, style: header},
+				xrow{cols: []xcol{{value: feature.Amendment, colspan: 2, list: b.listKind("amendment")}}, hasValue: true},
 			)
 		}
 		return result
@@ -205,8 +399,8 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 		var content []xrow
 		if len(feature.Resources) > 0 {
 			content = append(content,
-				xrow{cols: []xcol{{value: "File Usage:", bold: true, colspan: 2}}, bgColor: c1},
-				xrow{cols: []xcol{{value: "Table/File", bold: true}, {value: "Usage", bold: true}}, bgColor: c2},
+				xrow{cols: []xcol{{value: "File Usage:", bold: true, colspan: 2}}, style: header},
+				xrow{cols: []xcol{{value: "Table/File", bold: true}, {value: "Usage", bold: true}}, style: subheader},
 			)
 		}
 		for _, res := range feature.Resources {
@@ -217,32 +411,42 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 
 	/* --------------------------------- SCREEN --------------------------------- */
 	if len(feature.Screens) > 0 {
-		docb.AddParagraph().AddTable(func(tb *TableBuilder) {
-			tb.SetWidthPercent(100).SetBorders(func(b *Borders) { b.SetBorderAll(bs, bc, bt) }).
-				AddRow(func(rb *RowBuilder) {
-					rb.AddCell(func(cb *CellBuilder) {
-						cb.SetText("Screen Used:").SetBold().SetColspan(2).SetBackgroundColor(c1)
+		docb.AddParagraph().AddTable(func(tb *docx.TableBuilder) {
+			tb.SetWidthPercent(100).SetBorders(func(b *docx.Borders) { b.SetBorderAll(bs, bc, bt) }).
+				AddRow(func(rb *docx.RowBuilder) {
+					rb.AddCell(func(cb *docx.CellBuilder) {
+						cb.SetText("Screen Used:").SetStyle(header).SetColspan(2)
 					})
 				})
 			for _, scr := range feature.Screens {
-				docb.AddTable(func(tb *TableBuilder) {
-					tb.SetWidthPercent(100).SetBorders(func(b *Borders) { b.SetBorderAll(bs, bc, bt) }).
-						AddRow(func(rb *RowBuilder) {
+				docb.AddTable(func(tb *docx.TableBuilder) {
+					tb.SetWidthPercent(100).SetBorders(func(b *docx.Borders) { b.SetBorderAll(bs, bc, bt) }).
+						AddRow(func(rb *docx.RowBuilder) {
 							rb.
-								AddCell(func(cb *CellBuilder) { cb.SetText("Screen ID").SetBold().SetWidthPercent(wd).SetBackgroundColor(c2) }).
-								AddCell(func(cb *CellBuilder) { cb.SetText("Name").SetBold().SetBackgroundColor(c2) })
+								AddCell(func(cb *docx.CellBuilder) {
+									cb.SetText("Screen ID").SetStyle(subheader).SetWidthPercent(wd)
+								}).
+								AddCell(func(cb *docx.CellBuilder) { cb.SetText("Name").SetStyle(subheader) })
 						})
-					tb.AddRow(func(rb *RowBuilder) {
+					tb.AddRow(func(rb *docx.RowBuilder) {
 						rb.
-							AddCell(func(cb *CellBuilder) { cb.SetText(scr.Id).SetWidthPercent(wd) }).
-							AddCell(func(cb *CellBuilder) { cb.SetText(scr.Name) })
+							AddCell(func(cb *docx.CellBuilder) {
+								cb.SetText(scr.Id).SetStyle(styles.body).SetWidthPercent(wd).DisableLinks()
+								for _, id := range ToStrArray(scr.Id) {
+									if name := index.ScreenBookmark(id); !marked[name] {
+										cb.AddBookmark(name)
+										marked[name] = true
+									}
+								}
+							}).
+							AddCell(func(cb *docx.CellBuilder) { cb.SetText(scr.Name).SetStyle(styles.body) })
 					})
 					if xstrings.IsNotBlank(scr.Image.File) {
-						tb.AddRow(func(rb *RowBuilder) {
-							rb.AddCell(func(cb *CellBuilder) {
-								cb.SetColspan(2).
-									AddParagraph().AddParagraph(func(pb *ParagraphBuilder) {
-									pb.SetAlignment(wml.ST_JcCenter).AddImage(func(ip *ImageProperty) { ip.SetFile(scr.Image.File).SetWidth(float64(scr.Image.Width)) })
+						tb.AddRow(func(rb *docx.RowBuilder) {
+							rb.AddCell(func(cb *docx.CellBuilder) {
+								cb.SetColspan(2).SetStyle(styles.body).
+									AddParagraph().AddParagraph(func(pb *docx.ParagraphBuilder) {
+									pb.SetAlignment(wml.ST_JcCenter).AddImage(func(ip *docx.ImageProperty) { ip.SetFile(scr.Image.File).SetWidth(float64(scr.Image.Width)) })
 								})
 							})
 						})
@@ -257,14 +461,14 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 	createTable(true, func() []xrow {
 		var content []xrow
 		if len(feature.Input) > 0 {
-			content = append(content, xrow{cols: []xcol{{value: "Input:", bold: true, colspan: 2, widthPercent: wd}}, bgColor: c1})
+			content = append(content, xrow{cols: []xcol{{value: "Input:", bold: true, colspan: 2, widthPercent: wd}}, style: header})
 		}
 		for i, input := range feature.Input {
 			content = append(content,
-				xrow{cols: []xcol{{value: fmt.Sprintf("%d. %s", i+1, input.Name), bold: true, colspan: 2}}, bgColor: c2},
-				xrow{cols: []xcol{{value: "Fields", bold: true}, {value: input.Fields}}, hasValue: true},
-				xrow{cols: []xcol{{value: "Constraints", bold: true}, {value: input.Constraints}}, hasValue: true},
-				xrow{cols: []xcol{{value: "Remarks", bold: true}, {value: input.Remarks}}, hasValue: true},
+				xrow{cols: []xcol{{value: fmt.Sprintf("%d. %s", i+1, input.Name), bold: true, colspan: 2}}, style: subheader},
+				xrow{cols: []xcol{{value: "Fields", bold: true}, {value: input.Fields, list: b.listKind("input.fields")}}, hasValue: true},
+				xrow{cols: []xcol{{value: "Constraints", bold: true}, {value: input.Constraints, list: b.listKind("input.cons")}}, hasValue: true},
+				xrow{cols: []xcol{{value: "Remarks", bold: true}, {value: input.Remarks, list: b.listKind("input.remarks")}}, hasValue: true},
 			)
 		}
 		return content
@@ -275,11 +479,11 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 		var content []xrow
 		if len(feature.Parameters) > 0 {
 			content = append(content,
-				xrow{cols: []xcol{{value: "Input Parameters:", bold: true, colspan: 5}}, bgColor: c1},
+				xrow{cols: []xcol{{value: "Input Parameters:", bold: true, colspan: 5}}, style: header, header: true},
 				xrow{cols: []xcol{
 					{value: "Input #", bold: true},
 					{value: "Fields", bold: true}, {value: "Data Items", bold: true}, {value: "I/O", bold: true, alignment: wml.ST_JcCenter}, {value: "Processing Remarks", bold: true},
-				}, bgColor: c2},
+				}, style: subheader, header: true},
 			)
 			for i, param := range feature.Parameters {
 				content = append(content, xrow{cols: []xcol{
@@ -287,23 +491,23 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 					{value: param.Field, allowEmpty: true},
 					{value: param.Data, allowEmpty: true},
 					{value: param.IO, allowEmpty: true},
-					{value: param.Remarks, allowEmpty: true},
+					{value: param.Remarks, allowEmpty: true, list: b.listKind("parameters.remarks")},
 				}})
 			}
 		}
 		return content
-	})
+	}, 8, 20, 20, 8, 44)
 
 	/* -------------------------------- SCENARIO -------------------------------- */
 	createTable(true, func() []xrow {
 		var content []xrow
 		if len(feature.Scenarios) > 0 {
-			content = append(content, xrow{cols: []xcol{{value: "Processign Logic:", bold: true, colspan: 2}}, bgColor: c1})
+			content = append(content, xrow{cols: []xcol{{value: "Processign Logic:", bold: true, colspan: 2}}, style: header})
 		}
 		for i, scn := range feature.Scenarios {
-			content = append(content, xrow{cols: []xcol{{value: fmt.Sprintf("%d. %s", i+1, scn.Name), bold: true, colspan: 2}}, bgColor: c2})
+			content = append(content, xrow{cols: []xcol{{value: fmt.Sprintf("%d. %s", i+1, scn.Name), bold: true, colspan: 2}}, style: subheader})
 			for _, action := range scn.Desc {
-				keyword, others := b.splitGherkinWord(action)
+				keyword, others := SplitGherkinWord(action)
 				content = append(content, xrow{cols: []xcol{{value: keyword, bold: true, widthPercent: 10}, {value: others}}})
 			}
 		}
@@ -321,8 +525,8 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 				value := reflect.ValueOf(feature.Others).Field(index)
 				if !value.IsNil() {
 					content = append(content,
-						xrow{cols: []xcol{{value: textMap[fld.Name]}}, bgColor: c1},
-						xrow{cols: []xcol{{value: value.Interface(), numbering: nd}}},
+						xrow{cols: []xcol{{value: textMap[fld.Name]}}, style: header},
+						xrow{cols: []xcol{{value: value.Interface(), list: b.listKind("others." + strings.Split(fld.Tag.Get("yaml"), ",")[0]), code: fld.Name == "Program"}}},
 					)
 				}
 			}
@@ -335,62 +539,77 @@ func (b *Builder) constructFeature(docb *DocumentBuilder, feature *Feature) erro
 		var content []xrow
 		if len(feature.Tests) > 0 {
 			content = append(content,
-				xrow{cols: []xcol{{value: "Unit Test Records:", bold: true, colspan: 4}}, bgColor: c1},
+				xrow{cols: []xcol{{value: "Unit Test Records:", bold: true, colspan: 4}}, style: header, header: true},
 				xrow{cols: []xcol{
 					{value: "Test #", bold: true},
-					{value: "Test Description", bold: true}, {value: "Expected Result", bold: true}, {value: "Actual Result", bold: true}}, bgColor: c2},
+					{value: "Test Description", bold: true}, {value: "Expected Result", bold: true}, {value: "Actual Result", bold: true}}, style: subheader, header: true},
 			)
 			for i, param := range feature.Tests {
 				content = append(content, xrow{cols: []xcol{
 					{value: fmt.Sprintf("%d", i+1)},
-					{value: param.Desc, allowEmpty: true},
-					{value: param.Expect, allowEmpty: true},
-					{value: param.Actual, allowEmpty: true},
+					{value: param.Desc, allowEmpty: true, list: b.listKind("tests.desc")},
+					{value: param.Expect, allowEmpty: true, list: b.listKind("tests.expect")},
+					{value: param.Actual, allowEmpty: true, list: b.listKind("tests.actual")},
 				}})
 			}
 		}
 		return content
-	})
+	}, 8, 32, 30, 30)
 
 	return nil
 }
 
+// lists of the fields numbered by default, see config lists
+var defaultLists = map[string]docx.ListKind{
+	"others.reference": docx.ListBullet,
+	"others.limits":    docx.ListBullet,
+	"others.remarks":   docx.ListBullet,
+}
+
+// listKind returns the numbering of the multi-line field, e.g. input.cons
+func (b *Builder) listKind(field string) docx.ListKind {
+	if name, ok := b.config.Lists[field]; ok {
+		kind, _ := docx.ParseListKind(name)
+		return kind
+	}
+	return defaultLists[field]
+}
+
 func (b *Builder) loadData(file string) (*ProgSpec, error) {
-	yamlFile, err := os.ReadFile(file)
+	loader := newSpecLoader(b.options.Format, b.baseDir(file))
+	loader.overlay = b.options.Overlay
+	node, err := loader.load(file)
+	b.loaded = append(b.loaded, loader.files...)
 	if err != nil {
 		return nil, eris.Wrap(err, "failed to read the file")
 	}
+	if err := resolveInheritance(node); err != nil {
+		return nil, eris.Wrapf(err, "failed to resolve the defaults of the file %s", file)
+	}
 	var d ProgSpec
-	if err := yaml.Unmarshal(yamlFile, &d); err != nil {
-		return nil, eris.Wrapf(err, "failed to unmarshal the file %s", file)
+	if node != nil {
+		if err := node.Decode(&d); err != nil {
+			return nil, eris.Wrapf(err, "failed to unmarshal the file %s", file)
+		}
+		setPositions(node, &d, loader.origins)
 	}
 	return &d, nil
 }
 
-func (b *Builder) resolveInputFile(ifile string) (*[]string, error) {
-	files := []string{}
-	ifiles := strings.Split(ifile, ",")
-	for _, ifile := range ifiles {
-		fs, err := filepath.Glob(ifile)
-		if err != nil {
-			return nil, eris.Wrap(err, "failed to glob the file")
-		}
-		if len(fs) == 0 {
-			return nil, eris.Errorf("no such file: %s", ifile)
-		}
-		files = append(files, fs...)
-	}
-	sort.Strings(files)
-	return &files, nil
-}
+// GherkinKeywords are the leading keywords of the scenario steps
+var GherkinKeywords = []string{"given", "when", "then", "and", "but"}
 
-func (b *Builder) splitGherkinWord(s string) (string, string) {
+// SplitGherkinWord splits the leading keyword (given, when, then, and, but) from the step
+func SplitGherkinWord(s string) (string, string) {
 	if xstrings.IsBlank(s) {
 		return "", ""
 	}
 	s = strings.TrimSpace(s)
 	parts := strings.Split(s, " ")
-	if funk.Contains([]string{"given", "when", "then", "and", "but"}, strings.ToLower(parts[0])) {
+	if funk.Contains(GherkinKeywords, strings.ToLower(parts[0])) {
+		if len(parts) == 1 {
+			return parts[0], ""
+		}
 		return parts[0], s[len(parts[0])+1:]
 	}
 	return "", s
@@ -412,3 +631,8 @@ func (b *Builder) isAllFieldsInStructEmpty(value interface{}) bool {
 	}
 	return true
 }
+
+// ToStrArray converts the string, string array or other value to string array, see docx.ToStrArray
+func ToStrArray(v interface{}) []string {
+	return docx.ToStrArray(v)
+}
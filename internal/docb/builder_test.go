@@ -0,0 +1,24 @@
+package docb
+
+import "testing"
+
+func TestSplitGherkinWord(t *testing.T) {
+	tests := []struct {
+		step, keyword, rest string
+	}{
+		{"", "", ""},
+		{"  ", "", ""},
+		{"When", "When", ""},
+		{" then ", "then", ""},
+		{"Given the user logs in", "Given", "the user logs in"},
+		{"AND  saves", "AND", " saves"},
+		{"Whenever it fails", "", "Whenever it fails"},
+		{"the user saves", "", "the user saves"},
+	}
+	for _, tt := range tests {
+		keyword, rest := SplitGherkinWord(tt.step)
+		if keyword != tt.keyword || rest != tt.rest {
+			t.Errorf("SplitGherkinWord(%q) = %q, %q, want %q, %q", tt.step, keyword, rest, tt.keyword, tt.rest)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package docb
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"gopkg.in/yaml.v3"
+)
+
+// codeKey is the key of the code block, e.g. program: {code: {lang: sql, file: proc.sql}}
+const codeKey = "code"
+
+// Code is the code block of the text field
+type Code struct {
+	Lang    string // sql, java, javascript or go for the highlighting
+	Text    string
+	Numbers bool // show the line numbers
+}
+
+// CodeOf returns the code block of the value, i.e. the mapping of the code key. The text is the code as
+// well if plain is true, e.g. the program listing.
+func CodeOf(value interface{}, plain bool) (*Code, bool) {
+	switch t := value.(type) {
+	case map[string]interface{}:
+		v, ok := t[codeKey]
+		if !ok {
+			return nil, false
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			code := &Code{}
+			code.Lang, _ = m["lang"].(string)
+			code.Text, _ = m["text"].(string)
+			code.Numbers, _ = m["numbers"].(bool)
+			return code, true
+		}
+		return &Code{Text: strings.Join(ToStrArray(v), "\n")}, true
+	case string, []interface{}, []string:
+		if plain {
+			return &Code{Text: strings.Join(ToStrArray(t), "\n")}, true
+		}
+	}
+	return nil, false
+}
+
+// loadCode replaces the file of the code block by the text of the file, the file is relative to the spec
+func (l *specLoader) loadCode(dir string, value *yaml.Node) error {
+	var ref struct {
+		File string `yaml:"file"`
+		Text string `yaml:"text"`
+	}
+	if err := value.Decode(&ref); err != nil {
+		return eris.Wrapf(err, "invalid code at line %d in %s", value.Line, l.describe(""))
+	}
+	if ref.Text != "" {
+		return eris.Errorf("code at line %d in %s has both file and text", value.Line, l.describe(""))
+	}
+	file := ref.File
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	l.files = append(l.files, file)
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return eris.Wrapf(err, "failed to resolve the path of %s", file)
+	}
+	content, err := l.read(file, path)
+	if err != nil {
+		return eris.Wrapf(err, "failed to read the code %s in %s", file, l.describe(""))
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value == "file" {
+			value.Content[i].Value = "text"
+			text := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.LiteralStyle, Value: string(content), Line: value.Content[i+1].Line, Column: value.Content[i+1].Column}
+			l.origins[text] = l.origins[value.Content[i+1]]
+			value.Content[i+1] = text
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package docb
+
+import (
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+	"github.com/zrs01/pst/internal/config"
+)
+
+// Dependencies returns the files read by the build with the same arguments: config file, template,
+// input files, directories searched for the input, .pstignore files, included files and images. A
+// missing file is still returned so that it can be watched.
+func Dependencies(cfile, ifile string, tfile string, opts ...Options) ([]string, error) {
+	ncfg, err := config.NewConfig(cfile)
+	if err != nil {
+		return []string{cfile}, eris.Wrapf(err, "failed to load the configuration file %s", cfile)
+	}
+	b := &Builder{ifile: ifile, dfile: tfile, config: ncfg}
+	if len(opts) > 0 {
+		b.options = opts[0]
+	}
+
+	files := []string{}
+	for _, file := range []string{cfile, tfile} {
+		if xstrings.IsNotBlank(file) {
+			files = append(files, file)
+		}
+	}
+	paths, err := inputPaths(ifile)
+	files = append(files, paths...)
+	if err != nil {
+		return files, err
+	}
+	specs, err := b.load()
+	files = append(files, b.loaded...)
+	if err != nil {
+		return files, err
+	}
+	for _, spec := range append(specs, b.appendices...) {
+		for _, module := range spec.data.Modules {
+			for _, feature := range module.Features {
+				for _, screen := range feature.Screens {
+					if xstrings.IsNotBlank(screen.Image.File) {
+						files = append(files, screen.Image.File)
+					}
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// InputPaths returns the directories searched for the comma separated input and the .pstignore
+// files, a file created in them may change the input files
+func InputPaths(ifile string) ([]string, error) {
+	return inputPaths(ifile)
+}
+
+// ResolveInputFiles resolves the comma separated input, see the input flag
+func ResolveInputFiles(ifile string) ([]string, error) {
+	files, err := (&Builder{}).resolveInputFile(ifile)
+	if err != nil {
+		return nil, err
+	}
+	return *files, nil
+}
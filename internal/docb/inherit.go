@@ -0,0 +1,197 @@
+package docb
+
+import (
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultsKey = "defaults" // default values of the features in the spec or module
+	extendsKey  = "extends"  // id of the feature to inherit from
+	appendMark  = "+"        // e.g. "reference+:" appends to the inherited list instead of replacing it
+)
+
+// keys never inherited from the extended feature
+var notInheritedKeys = []string{"id", extendsKey}
+
+type inheritFeature struct {
+	id       string
+	node     *yaml.Node
+	defaults *yaml.Node // merged defaults of the spec and module
+}
+
+type inheritResolver struct {
+	features map[string]*inheritFeature
+	resolved map[*yaml.Node]bool
+	chain    []string
+}
+
+// resolveInheritance merges the defaults and the extended feature into each feature.
+// Precedence: feature > extended feature > module defaults > spec defaults.
+// Mappings are merged, lists and values of the feature replace the inherited ones unless the key ends with "+".
+func resolveInheritance(root *yaml.Node) error {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	specDefaults := removeMappingValue(root, defaultsKey)
+	modules := findMappingValue(root, "modules")
+	if modules == nil || modules.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	r := &inheritResolver{features: map[string]*inheritFeature{}, resolved: map[*yaml.Node]bool{}}
+	all := []*inheritFeature{}
+	for _, module := range modules.Content {
+		if module.Kind != yaml.MappingNode {
+			continue
+		}
+		defaults := removeMappingValue(module, defaultsKey)
+		if specDefaults != nil {
+			if defaults == nil {
+				defaults = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			inheritNode(defaults, specDefaults)
+		}
+		features := findMappingValue(module, "features")
+		if features == nil || features.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, node := range features.Content {
+			if node.Kind != yaml.MappingNode {
+				continue
+			}
+			f := &inheritFeature{node: node, defaults: defaults}
+			if id := findMappingValue(node, "id"); id != nil && id.Kind == yaml.ScalarNode {
+				f.id = id.Value
+				if _, ok := r.features[f.id]; !ok {
+					r.features[f.id] = f
+				}
+			}
+			all = append(all, f)
+		}
+	}
+
+	for _, f := range all {
+		if err := r.resolve(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *inheritResolver) resolve(f *inheritFeature) error {
+	if r.resolved[f.node] {
+		return nil
+	}
+	for _, id := range r.chain {
+		if id == f.id {
+			return eris.Errorf("inheritance cycle detected: %s -> %s", strings.Join(r.chain, " -> "), f.id)
+		}
+	}
+	r.chain = append(r.chain, f.id)
+	defer func() { r.chain = r.chain[:len(r.chain)-1] }()
+
+	if extends := removeMappingValue(f.node, extendsKey); extends != nil {
+		parent, ok := r.features[extends.Value]
+		if !ok {
+			return eris.Errorf("feature %s extends unknown feature %s (line %d)", f.id, extends.Value, extends.Line)
+		}
+		if err := r.resolve(parent); err != nil {
+			return err
+		}
+		inheritNode(f.node, parent.node, notInheritedKeys...)
+	}
+	if f.defaults != nil {
+		inheritNode(f.node, f.defaults, notInheritedKeys...)
+	}
+	normalizeAppendKeys(f.node)
+	r.resolved[f.node] = true
+	return nil
+}
+
+// inheritNode merges the parent mapping into the target mapping, the target wins
+func inheritNode(target, parent *yaml.Node, excludes ...string) {
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		key, value := parent.Content[i], parent.Content[i+1]
+		name := strings.TrimSuffix(key.Value, appendMark)
+		if isExcludedKey(name, excludes) {
+			continue
+		}
+		if existing := findMappingValue(target, name); existing != nil {
+			if existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+				inheritNode(existing, value)
+			}
+			continue
+		}
+		if appended := findMappingKey(target, name+appendMark); appended != nil {
+			list := findMappingValue(target, name+appendMark)
+			if list.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode {
+				list.Content = append(copyNode(value).Content, list.Content...)
+			}
+			appended.Value = name
+			continue
+		}
+		target.Content = append(target.Content, copyNode(key), copyNode(value))
+		target.Content[len(target.Content)-2].Value = name
+	}
+}
+
+// normalizeAppendKeys removes the append mark of the keys which have nothing to append to
+func normalizeAppendKeys(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			node.Content[i].Value = strings.TrimSuffix(node.Content[i].Value, appendMark)
+			normalizeAppendKeys(node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			normalizeAppendKeys(item)
+		}
+	}
+}
+
+func isExcludedKey(key string, excludes []string) bool {
+	for _, exclude := range excludes {
+		if key == exclude {
+			return true
+		}
+	}
+	return false
+}
+
+func findMappingKey(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+// removeMappingValue removes the key from the mapping and returns its value
+func removeMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// copyNode returns a deep copy so that the inherited node can be modified independently
+func copyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	c := *node
+	c.Content = nil
+	for _, item := range node.Content {
+		c.Content = append(c.Content, copyNode(item))
+	}
+	return &c
+}
@@ -0,0 +1,167 @@
+package docb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveInheritance(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string // YAML after the inheritance, or the error
+	}{
+		{
+			name: "spec and module defaults",
+			spec: `
+defaults: {mode: Batch, env: {languages: Go, source: git}}
+modules:
+  - name: M
+    defaults: {env: {languages: Java}}
+    features:
+      - {id: A}
+      - {id: B, mode: Online}
+`,
+			want: `
+modules:
+  - name: M
+    features:
+      - {id: A, env: {languages: Java, source: git}, mode: Batch}
+      - {id: B, mode: Online, env: {languages: Java, source: git}}
+`,
+		},
+		{
+			name: "extends with precedence",
+			spec: `
+modules:
+  - name: M
+    defaults: {mode: Batch, name: Default}
+    features:
+      - {id: B, extends: A, desc: own}
+      - {id: A, name: Parent, desc: parent, mode: Online}
+`,
+			want: `
+modules:
+  - name: M
+    features:
+      - {id: B, desc: own, name: Parent, mode: Online}
+      - {id: A, name: Parent, desc: parent, mode: Online}
+`,
+		},
+		{
+			name: "list replaced or appended",
+			spec: `
+modules:
+  - name: M
+    features:
+      - {id: A, tests: [{desc: t1}], others: {reference: [R1]}}
+      - {id: B, extends: A, tests: [{desc: t2}], others: {reference+: [R2]}}
+      - {id: C, extends: A, tests+: [{desc: t3}]}
+`,
+			want: `
+modules:
+  - name: M
+    features:
+      - {id: A, tests: [{desc: t1}], others: {reference: [R1]}}
+      - {id: B, tests: [{desc: t2}], others: {reference: [R1, R2]}}
+      - {id: C, tests: [{desc: t1}, {desc: t3}], others: {reference: [R1]}}
+`,
+		},
+		{
+			name: "append without inherited list",
+			spec: `
+modules:
+  - name: M
+    features:
+      - {id: A, tests+: [{desc: t1}]}
+`,
+			want: `
+modules:
+  - name: M
+    features:
+      - {id: A, tests: [{desc: t1}]}
+`,
+		},
+		{
+			name: "chain across modules",
+			spec: `
+modules:
+  - name: M1
+    features:
+      - {id: C, extends: B}
+  - name: M2
+    features:
+      - {id: B, extends: A, mode: Online}
+      - {id: A, name: Base, mode: Batch}
+`,
+			want: `
+modules:
+  - name: M1
+    features:
+      - {id: C, mode: Online, name: Base}
+  - name: M2
+    features:
+      - {id: B, mode: Online, name: Base}
+      - {id: A, name: Base, mode: Batch}
+`,
+		},
+		{
+			name: "unknown feature",
+			spec: `
+modules:
+  - name: M
+    features:
+      - {id: A, extends: Z}
+`,
+			want: "error: feature A extends unknown feature Z",
+		},
+		{
+			name: "cycle",
+			spec: `
+modules:
+  - name: M
+    features:
+      - {id: A, extends: B}
+      - {id: B, extends: A}
+`,
+			want: "error: inheritance cycle detected: A -> B -> A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parseYAML(t, tt.spec)
+			err := resolveInheritance(root)
+			if strings.HasPrefix(tt.want, "error: ") {
+				if err == nil || !strings.Contains(err.Error(), strings.TrimPrefix(tt.want, "error: ")) {
+					t.Fatalf("want %s, got %v", tt.want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := canonicalYAML(t, root), canonicalYAML(t, parseYAML(t, tt.want)); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestInheritedNodesAreCopies(t *testing.T) {
+	root := parseYAML(t, `
+modules:
+  - name: M
+    defaults: {env: {languages: Go}}
+    features:
+      - {id: A}
+      - {id: B}
+`)
+	if err := resolveInheritance(root); err != nil {
+		t.Fatal(err)
+	}
+	features := findMappingValue(findMappingValue(root, "modules").Content[0], "features").Content
+	findMappingValue(findMappingValue(features[0], "env"), "languages").Value = "Java"
+	if got := findMappingValue(findMappingValue(features[1], "env"), "languages").Value; got != "Go" {
+		t.Errorf("feature B shares the node of feature A, languages %s", got)
+	}
+}
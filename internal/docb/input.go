@@ -0,0 +1,300 @@
+package docb
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/thoas/go-funk"
+)
+
+// ignoreFile lists the files excluded from the directory and wildcard input, same as .gitignore in short
+const ignoreFile = ".pstignore"
+
+// extensions of the spec file searched in the directory input
+var specExtensions = []string{".yml", ".yaml", ".json", ".toml"}
+
+// resolveInputFile resolves the comma separated input to files, each input could be
+// file, directory (recursive), wildcard (filepath.Glob) or recursive wildcard (**)
+func (b *Builder) resolveInputFile(ifile string) (*[]string, error) {
+	files := []string{}
+	ifiles := strings.Split(ifile, ",")
+	for _, ifile := range ifiles {
+		ifile = strings.TrimSpace(ifile)
+		if ifile == StdStream {
+			files = append(files, ifile)
+			continue
+		}
+
+		var fs []string
+		var err error
+		if info, statErr := os.Stat(ifile); statErr == nil && info.IsDir() {
+			fs, err = walkInput(ifile, func(rel string) bool {
+				return funk.ContainsString(specExtensions, strings.ToLower(filepath.Ext(rel)))
+			})
+		} else if strings.Contains(ifile, "**") {
+			fs, err = globRecursive(ifile)
+		} else if strings.ContainsAny(ifile, "*?[") {
+			fs, err = filepath.Glob(ifile)
+			if err == nil {
+				fs, err = excludeIgnored(".", fs)
+			}
+		} else {
+			fs, err = filepath.Glob(ifile)
+		}
+		if err != nil {
+			return nil, eris.Wrap(err, "failed to glob the file")
+		}
+		if len(fs) == 0 {
+			return nil, eris.Errorf("no such file: %s", ifile)
+		}
+		files = append(files, fs...)
+	}
+	files = uniqueFiles(files)
+	sort.Strings(files)
+	return &files, nil
+}
+
+// uniqueFiles removes the files matched by more than one input, e.g. specs and specs/**/*.yml
+func uniqueFiles(files []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, file := range files {
+		key := file
+		if file != StdStream {
+			if path, err := filepath.Abs(file); err == nil {
+				key = path
+			}
+		}
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, file)
+		}
+	}
+	return unique
+}
+
+// globRecursive supports ** to match zero or more directories, e.g. specs/**/*.yml
+func globRecursive(pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(pattern)
+	root := globRoot(pattern)
+	re, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return walkInput(root, func(rel string) bool {
+		return re.MatchString(filepath.ToSlash(filepath.Join(root, rel)))
+	})
+}
+
+// globRoot returns the directory before the ** of the pattern
+func globRoot(pattern string) string {
+	root := pattern[:strings.Index(pattern, "**")]
+	if i := strings.LastIndex(root, "/"); i >= 0 {
+		root = root[:i]
+	} else {
+		root = "."
+	}
+	if root == "" {
+		root = "/"
+	}
+	return root
+}
+
+// walkInput returns the files under the root matched and not ignored by the .pstignore files
+func walkInput(root string, match func(rel string) bool) ([]string, error) {
+	files := []string{}
+	err := walkTree(root, func(path string, d fs.DirEntry) {
+		rel, _ := filepath.Rel(root, path)
+		if !d.IsDir() && match(rel) {
+			files = append(files, path)
+		}
+	})
+	return files, err
+}
+
+// walkTree visits the files and directories under the root not ignored by the .pstignore files
+func walkTree(root string, visit func(path string, d fs.DirEntry)) error {
+	ignores, err := loadIgnorePatterns(".")
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			patterns, err := loadIgnorePatterns(path)
+			if err != nil {
+				return err
+			}
+			ignores = append(ignores, patterns...)
+		}
+		if rel != "." && isIgnored(ignores, path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		visit(path, d)
+		return nil
+	})
+}
+
+// inputPaths returns the directories searched by the directory and wildcard input and the .pstignore
+// files applied, as a file created in them may change the input files. The .pstignore of the working
+// directory is returned even if missing so that it can be watched.
+func inputPaths(ifile string) ([]string, error) {
+	paths := []string{}
+	searched := false
+	for _, ifile := range strings.Split(ifile, ",") {
+		ifile = strings.TrimSpace(ifile)
+		root := ""
+		if info, err := os.Stat(ifile); err == nil && info.IsDir() {
+			root = ifile
+		} else if strings.Contains(ifile, "**") {
+			root = globRoot(filepath.ToSlash(ifile))
+		} else if strings.ContainsAny(ifile, "*?[") {
+			// the directories of the files matched, the wildcard in the directory is not recursive
+			dirs, err := filepath.Glob(filepath.Dir(ifile))
+			if err != nil {
+				return paths, eris.Wrap(err, "failed to glob the file")
+			}
+			for _, dir := range dirs {
+				if info, err := os.Stat(dir); err == nil && info.IsDir() {
+					paths = append(paths, dir)
+				}
+			}
+			searched = true
+			continue
+		}
+		if root == "" {
+			continue
+		}
+		searched = true
+		err := walkTree(root, func(path string, d fs.DirEntry) {
+			if d.IsDir() || d.Name() == ignoreFile {
+				paths = append(paths, path)
+			}
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return paths, eris.Wrapf(err, "failed to walk %s", root)
+		}
+	}
+	if searched {
+		paths = append(paths, ignoreFile)
+	}
+	return uniqueFiles(paths), nil
+}
+
+func excludeIgnored(dir string, files []string) ([]string, error) {
+	ignores, err := loadIgnorePatterns(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	for _, file := range files {
+		if !isIgnored(ignores, file, false) {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+type ignorePattern struct {
+	dir     string // directory of the .pstignore
+	re      *regexp.Regexp
+	dirOnly bool
+}
+
+// loadIgnorePatterns reads the .pstignore in the directory, the pattern without slash matches the
+// file name in any level, otherwise it matches the path relative to the directory
+func loadIgnorePatterns(dir string) ([]ignorePattern, error) {
+	f, err := os.Open(filepath.Join(dir, ignoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, eris.Wrapf(err, "failed to read %s", filepath.Join(dir, ignoreFile))
+	}
+	defer f.Close()
+
+	patterns := []ignorePattern{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{dir: dir}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		if p.re, err = globToRegexp(line); err != nil {
+			return nil, eris.Wrapf(err, "invalid pattern %s in %s", line, filepath.Join(dir, ignoreFile))
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, scanner.Err()
+}
+
+func isIgnored(patterns []ignorePattern, path string, isDir bool) bool {
+	for _, p := range patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(p.dir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if p.re.MatchString(filepath.ToSlash(rel)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp converts the wildcard to regular expression, ** matches zero or more directories
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+	var sb strings.Builder
+	sb.WriteString("^(\\./)?")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			j := strings.IndexByte(pattern[i:], ']')
+			if j < 0 {
+				return nil, eris.Errorf("unclosed [ in %s", pattern)
+			}
+			sb.WriteString(pattern[i : i+j+1])
+			i += j
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,145 @@
+package docb
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestResolveInputFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".pstignore":              "# drafts\ndrafts/\n*.tmp.yml\n",
+		"specs/a.yml":             "",
+		"specs/b.json":            "",
+		"specs/notes.txt":         "",
+		"specs/old.tmp.yml":       "",
+		"specs/sub/c.yml":         "",
+		"specs/sub/.pstignore":    "/local.yml\n",
+		"specs/sub/local.yml":     "",
+		"specs/sub/sub/local.yml": "",
+		"specs/drafts/d.yml":      "",
+		"other/drafts.yml":        "",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		input string
+		want  []string // or the error
+	}{
+		{"specs", []string{"specs/a.yml", "specs/b.json", "specs/sub/c.yml", "specs/sub/sub/local.yml"}},
+		{"specs/*.yml", []string{"specs/a.yml"}},
+		{"specs/**/*.yml", []string{"specs/a.yml", "specs/sub/c.yml", "specs/sub/sub/local.yml"}},
+		{"specs/**/c.yml", []string{"specs/sub/c.yml"}},
+		{"specs/sub/local.yml", []string{"specs/sub/local.yml"}}, // the file named directly is not ignored
+		{"other/*.yml", []string{"other/drafts.yml"}},
+		{"specs, specs/**/*.yml", []string{"specs/a.yml", "specs/b.json", "specs/sub/c.yml", "specs/sub/sub/local.yml"}},
+		{"specs/a.yml,specs/*.yml,./specs/a.yml", []string{"specs/a.yml"}},
+		{"specs/b.json," + filepath.Join(dir, "specs/b.json"), []string{"specs/b.json"}},
+		{"specs/missing.yml", []string{"error: no such file: specs/missing.yml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			files, err := (&Builder{}).resolveInputFile(tt.input)
+			if err != nil {
+				if len(tt.want) != 1 || !strings.HasPrefix(tt.want[0], "error: ") || !strings.Contains(err.Error(), strings.TrimPrefix(tt.want[0], "error: ")) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			got := []string{}
+			for _, file := range *files {
+				got = append(got, filepath.ToSlash(filepath.Clean(file)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputPaths(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"specs/a.yml":            "",
+		"specs/sub/c.yml":        "",
+		"specs/sub/.pstignore":   "drafts/\n",
+		"specs/sub/drafts/d.yml": "",
+		"other/b.yml":            "",
+	})
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"specs", []string{".pstignore", "specs", "specs/sub", "specs/sub/.pstignore"}},
+		{"specs/**/*.yml", []string{".pstignore", "specs", "specs/sub", "specs/sub/.pstignore"}},
+		{"other/*.yml", []string{".pstignore", "other"}},
+		{"specs/a.yml", []string{}},
+		{"specs/a.yml, other/*.yml", []string{".pstignore", "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			paths, err := inputPaths(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, path := range paths {
+				got = append(got, filepath.ToSlash(filepath.Clean(path)))
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"*.yml", []string{"a.yml", "./a.yml"}, []string{"a/b.yml", "a.yaml"}},
+		{"specs/**/*.yml", []string{"specs/a.yml", "specs/x/y/a.yml"}, []string{"other/a.yml", "specs/a.json"}},
+		{"**/drafts", []string{"drafts", "a/b/drafts"}, []string{"drafts2", "a/drafts/b"}},
+		{"a?[0-9].yml", []string{"ab1.yml"}, []string{"a/1.yml", "abc.yml"}},
+	}
+	for _, tt := range tests {
+		re, err := globToRegexp(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range tt.match {
+			if !re.MatchString(s) {
+				t.Errorf("%s should match %s", tt.pattern, s)
+			}
+		}
+		for _, s := range tt.noMatch {
+			if re.MatchString(s) {
+				t.Errorf("%s should not match %s", tt.pattern, s)
+			}
+		}
+	}
+	if _, err := globToRegexp("a[.yml"); err == nil {
+		t.Error("unclosed [ should fail")
+	}
+}
@@ -0,0 +1,371 @@
+package docb
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/rotisserie/eris"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	includeTag = "!include" // e.g. features: [!include uf010a.yml]
+	includeKey = "include"  // e.g. include: [common.yml, module-a.yml]
+)
+
+// supported formats of the spec file
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// specLoader reads the spec file and resolves the includes relative to the including file
+type specLoader struct {
+	format   string         // format of the outermost file, detect by extension if blank
+	stdinDir string         // directory to resolve the includes of stdin
+	chain    []includeEntry // files being loaded, outermost first
+	files    []string       // files read including the included files
+	overlay  map[string][]byte
+	origins  map[*yaml.Node]string // file of the node, for the position of the features
+}
+
+type includeEntry struct {
+	name string // file name as written by the user
+	path string // absolute path for cycle detection
+}
+
+func newSpecLoader(format, stdinDir string) *specLoader {
+	return &specLoader{format: strings.ToLower(format), stdinDir: stdinDir, origins: map[*yaml.Node]string{}}
+}
+
+// load returns the root node of the file with all includes resolved, nil if the file is empty
+func (l *specLoader) load(file string) (*yaml.Node, error) {
+	if file == StdStream && len(l.chain) == 0 {
+		return l.loadStdin()
+	}
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to resolve the path of %s", l.describe(file))
+	}
+	for _, entry := range l.chain {
+		if entry.path == path {
+			return nil, eris.Errorf("include cycle detected: %s", l.describe(file))
+		}
+	}
+
+	l.files = append(l.files, file)
+	content, err := l.read(file, path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the file %s", l.describe(file))
+	}
+	format := l.format
+	if len(l.chain) > 0 || format == "" {
+		format = formatOf(file)
+	}
+	root, err := parseSpec(content, format)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to unmarshal the file %s", l.describe(file))
+	}
+	if root == nil {
+		return nil, nil
+	}
+	l.markOrigin(root, file)
+
+	l.chain = append(l.chain, includeEntry{name: file, path: path})
+	defer func() { l.chain = l.chain[:len(l.chain)-1] }()
+
+	if err := l.resolve(root, filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// read returns the content of the overlay if any, otherwise the file
+func (l *specLoader) read(file, path string) ([]byte, error) {
+	for _, name := range []string{file, path} {
+		if content, ok := l.overlay[name]; ok {
+			return content, nil
+		}
+	}
+	return os.ReadFile(file)
+}
+
+// loadStdin reads the spec from stdin, the includes are relative to the stdin directory
+func (l *specLoader) loadStdin() (*yaml.Node, error) {
+	content, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read from stdin")
+	}
+	format := l.format
+	if format == "" {
+		format = FormatYAML
+	}
+	root, err := parseSpec(content, format)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to unmarshal the stdin")
+	}
+	if root == nil {
+		return nil, nil
+	}
+	l.markOrigin(root, StdStream)
+
+	l.chain = append(l.chain, includeEntry{name: "<stdin>"})
+	defer func() { l.chain = l.chain[:len(l.chain)-1] }()
+
+	if err := l.resolve(root, l.stdinDir); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (l *specLoader) resolve(node *yaml.Node, dir string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == includeTag {
+			included, err := l.include(dir, node.Value)
+			if err != nil {
+				return err
+			}
+			*node = *included
+			l.origins[node] = l.origins[included]
+		}
+
+	case yaml.SequenceNode:
+		// an included list is spliced into the including list
+		var content []*yaml.Node
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode && item.Tag == includeTag {
+				included, err := l.include(dir, item.Value)
+				if err != nil {
+					return err
+				}
+				switch {
+				case isNull(included):
+					// empty fragment adds no item
+				case included.Kind == yaml.SequenceNode:
+					content = append(content, included.Content...)
+				default:
+					content = append(content, included)
+				}
+				continue
+			}
+			if err := l.resolve(item, dir); err != nil {
+				return err
+			}
+			content = append(content, item)
+		}
+		node.Content = content
+
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		var includes []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == includeKey {
+				includes = append(includes, value)
+				continue
+			}
+			if err := l.resolve(value, dir); err != nil {
+				return err
+			}
+			if key.Value == codeKey && value.Kind == yaml.MappingNode && findMappingValue(value, "file") != nil {
+				if err := l.loadCode(dir, value); err != nil {
+					return err
+				}
+			}
+			if _, ok := tableFields[key.Value]; ok && value.Kind == yaml.MappingNode && findMappingValue(value, tableKey) != nil {
+				list, err := l.loadTable(dir, key.Value, value, node)
+				if err != nil {
+					return err
+				}
+				value = list
+			}
+			content = append(content, key, value)
+		}
+		node.Content = content
+
+		for _, value := range includes {
+			files, err := l.includeFiles(value)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				included, err := l.include(dir, file)
+				if err != nil {
+					return err
+				}
+				if isNull(included) {
+					continue
+				}
+				if included.Kind != yaml.MappingNode {
+					return eris.Errorf("included file %s must contain a mapping (line %d)", l.describe(file), value.Line)
+				}
+				mergeNode(node, included)
+			}
+		}
+	}
+	return nil
+}
+
+func (l *specLoader) markOrigin(node *yaml.Node, file string) {
+	l.origins[node] = file
+	for _, item := range node.Content {
+		l.markOrigin(item, file)
+	}
+}
+
+// formatOf detects the format by the file extension, default is YAML
+func formatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return FormatJSON
+	case ".toml":
+		return FormatTOML
+	}
+	return FormatYAML
+}
+
+// parseSpec returns the root node of the content, JSON and TOML are converted to YAML node
+// so that they share the same includes, defaults and string or string array handling
+func parseSpec(content []byte, format string) (*yaml.Node, error) {
+	var value interface{}
+	switch format {
+	case FormatYAML:
+		var doc yaml.Node
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			return nil, nil
+		}
+		return doc.Content[0], nil
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		value = jsonNumbers(value)
+	case FormatTOML:
+		var m map[string]interface{}
+		if err := toml.Unmarshal(content, &m); err != nil {
+			return nil, err
+		}
+		value = m
+	default:
+		return nil, eris.Errorf("unsupported format %s", format)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// jsonNumbers converts the numbers of the decoded JSON to int64 or float64, yaml encodes json.Number as string
+func jsonNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonNumbers(item)
+		}
+	}
+	return value
+}
+
+// include loads the file relative to the directory of the including file
+func (l *specLoader) include(dir, file string) (*yaml.Node, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	node, err := l.load(file)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		// treat empty fragment as null
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}, nil
+	}
+	return node, nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// includeFiles accepts the value of the "include" key as string or string array
+func (l *specLoader) includeFiles(value *yaml.Node) ([]string, error) {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		return []string{value.Value}, nil
+	case yaml.SequenceNode:
+		files := []string{}
+		for _, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, eris.Errorf("invalid include at line %d in %s", item.Line, l.describe(""))
+			}
+			files = append(files, item.Value)
+		}
+		return files, nil
+	}
+	return nil, eris.Errorf("invalid include at line %d in %s", value.Line, l.describe(""))
+}
+
+// describe returns the include chain, e.g. main.yml -> module.yml -> fragment.yml
+func (l *specLoader) describe(file string) string {
+	names := []string{}
+	for _, entry := range l.chain {
+		names = append(names, entry.name)
+	}
+	if file != "" {
+		names = append(names, file)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// mergeNode merges the included mapping into the target: keys defined in the target win,
+// mappings are merged recursively and lists are concatenated with the included items first
+func mergeNode(target, included *yaml.Node) {
+	for i := 0; i+1 < len(included.Content); i += 2 {
+		key, value := included.Content[i], included.Content[i+1]
+		existing := findMappingValue(target, key.Value)
+		switch {
+		case existing == nil:
+			target.Content = append(target.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			mergeNode(existing, value)
+		case existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			existing.Content = append(append([]*yaml.Node{}, value.Content...), existing.Content...)
+		}
+	}
+}
+
+func findMappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package docb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeFiles writes the files into a temporary directory and returns the directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSpecLoaderInclude(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string // YAML of main.yml after the includes, or the error
+	}{
+		{
+			name: "list item",
+			files: map[string]string{
+				"main.yml": "features:\n  - !include a.yml\n  - id: B\n",
+				"a.yml":    "id: A\n",
+			},
+			want: "features:\n  - id: A\n  - id: B\n",
+		},
+		{
+			name: "list spliced",
+			files: map[string]string{
+				"main.yml": "features:\n  - !include a.yml\n  - id: C\n",
+				"a.yml":    "- id: A\n- id: B\n",
+			},
+			want: "features:\n  - id: A\n  - id: B\n  - id: C\n",
+		},
+		{
+			name: "empty fragment in list",
+			files: map[string]string{
+				"main.yml":  "features:\n  - !include empty.yml\n  - id: A\n",
+				"empty.yml": "# nothing yet\n",
+			},
+			want: "features:\n  - id: A\n",
+		},
+		{
+			name: "empty fragment as value",
+			files: map[string]string{
+				"main.yml":  "name: M\nfeatures: !include empty.yml\n",
+				"empty.yml": "",
+			},
+			want: "name: M\nfeatures: null\n",
+		},
+		{
+			name: "relative to including file",
+			files: map[string]string{
+				"main.yml":          "features:\n  - !include sub/a.yml\n",
+				"sub/a.yml":         "id: A\ntests: !include tests.yml\n",
+				"sub/tests.yml":     "- desc: t1\n",
+				"sub/sub/tests.yml": "- desc: wrong\n",
+			},
+			want: "features:\n  - id: A\n    tests:\n      - desc: t1\n",
+		},
+		{
+			name: "include key merged",
+			files: map[string]string{
+				"main.yml":   "include: [common.yml, empty.yml]\nname: M\ntags: [b]\n",
+				"common.yml": "name: C\nversion: 1\ntags: [a]\n",
+				"empty.yml":  "",
+			},
+			want: "name: M\ntags: [a, b]\nversion: 1\n",
+		},
+		{
+			name: "include key not mapping",
+			files: map[string]string{
+				"main.yml": "include: list.yml\n",
+				"list.yml": "- a\n",
+			},
+			want: "error: list.yml must contain a mapping",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"main.yml": "features:\n  - !include a.yml\n",
+				"a.yml":    "id: A\ntests: !include b.yml\n",
+				"b.yml":    "- !include a.yml\n",
+			},
+			want: "error: include cycle detected",
+		},
+		{
+			name: "same file twice",
+			files: map[string]string{
+				"main.yml": "features:\n  - !include a.yml\n  - !include a.yml\n",
+				"a.yml":    "id: A\n",
+			},
+			want: "features:\n  - id: A\n  - id: A\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			root, err := newSpecLoader("", "").load(filepath.Join(dir, "main.yml"))
+			if err != nil {
+				if !strings.HasPrefix(tt.want, "error: ") || !strings.Contains(err.Error(), strings.TrimPrefix(tt.want, "error: ")) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if strings.HasPrefix(tt.want, "error: ") {
+				t.Fatalf("want %s, got no error", tt.want)
+			}
+			got := canonicalYAML(t, root)
+			if want := canonicalYAML(t, parseYAML(t, tt.want)); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func parseYAML(t *testing.T, s string) *yaml.Node {
+	t.Helper()
+	node, err := parseSpec([]byte(s), FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+// canonicalYAML returns the YAML of the node without the styles and tags of the includes
+func canonicalYAML(t *testing.T, node *yaml.Node) string {
+	t.Helper()
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+	out, err := yaml.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestLoadNumbers(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"spec.json", `{"modules": [{"name": "M", "features": [{"id": 101, "desc": 1.5, "screens": [{"id": "S1", "image": {"width": 300}}]}]}]}`},
+		{"spec.toml", "[[modules]]\nname = \"M\"\n[[modules.features]]\nid = 101\ndesc = 1.5\n[[modules.features.screens]]\nid = \"S1\"\nimage = { width = 300 }\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			dir := writeFiles(t, map[string]string{tt.file: tt.content})
+			specs, err := Load("", filepath.Join(dir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			feature := specs[0].Data.Modules[0].Features[0]
+			if got := feature.Screens[0].Image.Width; got != 300 {
+				t.Errorf("width %d, want 300", got)
+			}
+			if got := strings.Join(ToStrArray(feature.Id), ""); got != "101" {
+				t.Errorf("id %s, want 101", got)
+			}
+			if got := strings.Join(ToStrArray(feature.Desc), ""); got != "1.5" {
+				t.Errorf("desc %s, want 1.5", got)
+			}
+		})
+	}
+}
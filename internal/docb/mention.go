@@ -0,0 +1,153 @@
+package docb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// word which could be an ID in the free text, e.g. UF011A or PG-GEN-002
+	idToken = regexp.MustCompile(`[A-Za-z0-9](?:[A-Za-z0-9_-]*[A-Za-z0-9])?`)
+	// prefix, number and suffix of the ID, e.g. UF + 011 + A
+	idShape = regexp.MustCompile(`^(.*?)(\d+)(\D*)$`)
+	// characters not allowed in the bookmark name
+	invalidBookmarkChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// IdIndex holds the feature and screen IDs of the specs to find the IDs mentioned in the free text
+type IdIndex struct {
+	Features map[string]*Feature // the first one if duplicated
+	Screens  map[string]*Screen
+	prefixes map[string]bool
+	marks    map[string]string // bookmark name by the prefixed ID, e.g. F_UF011A
+	used     map[string]bool   // bookmark names assigned
+	patterns []*regexp.Regexp
+}
+
+// Mention is the word shaped like an ID in the text, it may not resolve to any feature or screen
+type Mention struct {
+	Id         string
+	Start, End int // byte offsets in the text
+}
+
+// NewIdIndex indexes the specs, the word is a mention if it has the prefix of a known ID and a number
+// (e.g. UF099A for the known UF011A) or matches any of the patterns
+func NewIdIndex(specs []*ProgSpec, patterns ...*regexp.Regexp) *IdIndex {
+	x := &IdIndex{Features: map[string]*Feature{}, Screens: map[string]*Screen{}, prefixes: map[string]bool{}, patterns: patterns,
+		marks: map[string]string{}, used: map[string]bool{}}
+	for _, spec := range specs {
+		for i := range spec.Modules {
+			for j := range spec.Modules[i].Features {
+				feature := &spec.Modules[i].Features[j]
+				for _, id := range ToStrArray(feature.Id) {
+					x.add(id)
+					if _, ok := x.Features[id]; !ok {
+						x.Features[id] = feature
+						x.assignBookmark(featurePrefix, id)
+					}
+				}
+				for k := range feature.Screens {
+					for _, id := range ToStrArray(feature.Screens[k].Id) {
+						x.add(id)
+						if _, ok := x.Screens[id]; !ok {
+							x.Screens[id] = &feature.Screens[k]
+							x.assignBookmark(screenPrefix, id)
+						}
+					}
+				}
+			}
+		}
+	}
+	return x
+}
+
+func (x *IdIndex) add(id string) {
+	// the prefix should not be blank, otherwise every number is a mention
+	if m := idShape.FindStringSubmatch(id); m != nil && strings.TrimSpace(m[1]) != "" {
+		x.prefixes[m[1]] = true
+	}
+}
+
+// Resolves checks if the ID is a feature or screen
+func (x *IdIndex) Resolves(id string) bool {
+	_, feature := x.Features[id]
+	_, screen := x.Screens[id]
+	return feature || screen
+}
+
+// Mentions returns the words shaped like an ID in the text
+func (x *IdIndex) Mentions(text string) []Mention {
+	mentions := []Mention{}
+	for _, loc := range idToken.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+		if x.isMention(word) {
+			mentions = append(mentions, Mention{Id: word, Start: loc[0], End: loc[1]})
+		}
+	}
+	return mentions
+}
+
+func (x *IdIndex) isMention(word string) bool {
+	if x.Resolves(word) {
+		return true
+	}
+	if m := idShape.FindStringSubmatch(word); m != nil && x.prefixes[m[1]] {
+		return true
+	}
+	for _, p := range x.patterns {
+		if p.MatchString(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bookmarks returns the bookmark names of the feature headings and screen IDs by the ID, the feature
+// is the target if the screen has the same ID
+func (x *IdIndex) Bookmarks() map[string]string {
+	bookmarks := map[string]string{}
+	for id := range x.Screens {
+		bookmarks[id] = x.ScreenBookmark(id)
+	}
+	for id := range x.Features {
+		bookmarks[id] = x.FeatureBookmark(id)
+	}
+	return bookmarks
+}
+
+const (
+	featurePrefix = "F_"
+	screenPrefix  = "S_"
+)
+
+// FeatureBookmark returns the bookmark name of the feature heading
+func (x *IdIndex) FeatureBookmark(id string) string {
+	return x.marks[featurePrefix+id]
+}
+
+// ScreenBookmark returns the bookmark name of the screen ID in the screen table
+func (x *IdIndex) ScreenBookmark(id string) string {
+	return x.marks[screenPrefix+id]
+}
+
+// assignBookmark names the bookmark of the ID, Word requires the name starts with a letter, has
+// letters, digits and underscores only, and 40 characters at most. The IDs differ in the replaced
+// or truncated characters only, e.g. UF-01 and UF_01, are numbered to keep the names unique.
+func (x *IdIndex) assignBookmark(prefix, id string) {
+	base := prefix + invalidBookmarkChars.ReplaceAllString(id, "_")
+	name := truncate(base, 40)
+	for n := 2; x.used[name]; n++ {
+		suffix := fmt.Sprintf("_%d", n)
+		name = truncate(base, 40-len(suffix)) + suffix
+	}
+	x.used[name] = true
+	x.marks[prefix+id] = name
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package docb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	long := strings.Repeat("A", 45)
+	spec := &ProgSpec{Modules: []Module{{Features: []Feature{
+		{Id: "UF-01", Screens: []Screen{{Id: "UF-01"}, {Id: "SC.1"}}},
+		{Id: "UF_01"},
+		{Id: "UF_01_2"},
+		{Id: "UF-01"}, // duplicated, the first one is the target
+		{Id: long + "1"},
+		{Id: long + "2"},
+		{Id: "UF 01", Screens: []Screen{{Id: "SC_1"}}},
+	}}}}
+	x := NewIdIndex([]*ProgSpec{spec})
+
+	want := map[string]string{
+		"UF-01":    "F_UF_01",
+		"UF_01":    "F_UF_01_2",
+		"UF_01_2":  "F_UF_01_2_2",
+		long + "1": "F_" + long[:38],
+		long + "2": "F_" + long[:36] + "_2",
+		"UF 01":    "F_UF_01_3",
+		"SC.1":     "S_SC_1",
+		"SC_1":     "S_SC_1_2",
+	}
+	if got := x.Bookmarks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := x.ScreenBookmark("UF-01"); got != "S_UF_01" {
+		t.Errorf("got screen bookmark %s, want S_UF_01", got)
+	}
+	for _, name := range want {
+		if len(name) > 40 {
+			t.Errorf("bookmark %s is longer than 40 characters", name)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package docb
 
+import "strings"
+
 type ProgSpec struct {
-	Modules []Module `yaml:"modules,omitempty"`
+	Vars    map[string]string `yaml:"vars,omitempty"`
+	Modules []Module          `yaml:"modules,omitempty"`
 }
 
 type Module struct {
@@ -22,6 +25,21 @@ type Feature struct {
 	Scenarios  []Scenario  `yaml:"scenarios,omitempty"`
 	Others     Others      `yaml:"others,omitempty"`
 	Tests      []Test      `yaml:"tests,omitempty"`
+	Api        *Api        `yaml:"api,omitempty"` // operation of the OpenAPI document, see pst import-openapi
+	Pos        Position    `yaml:"-"`
+}
+
+// Api is the REST operation implemented by the feature
+type Api struct {
+	Method string `yaml:"method,omitempty"`
+	Path   string `yaml:"path,omitempty"`
+}
+
+func (a *Api) String() string {
+	if a == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ToUpper(a.Method) + " " + a.Path)
 }
 
 type Env struct {
@@ -36,6 +54,7 @@ type Screen struct {
 	Id    interface{} `yaml:"id,omitempty"`
 	Name  interface{} `yaml:"name,omitempty"`
 	Image Image       `yaml:"image,omitempty"`
+	Pos   Position    `yaml:"-"`
 }
 type Input struct {
 	Name        interface{} `yaml:"name,omitempty"`
@@ -48,10 +67,12 @@ type Parameter struct {
 	Data    interface{} `yaml:"data,omitempty"`
 	IO      interface{} `yaml:"io,omitempty"`
 	Remarks interface{} `yaml:"remarks,omitempty"`
+	Pos     Position    `yaml:"-"`
 }
 type Scenario struct {
 	Name interface{} `yaml:"name,omitempty"`
 	Desc []string    `yaml:"desc,omitempty"`
+	Pos  Position    `yaml:"-"`
 }
 type Image struct {
 	File  string `yaml:"file,omitempty"`
@@ -60,11 +81,13 @@ type Image struct {
 type Others struct {
 	Reference interface{} `yaml:"reference,omitempty"`
 	Limits    interface{} `yaml:"limits,omitempty"`
-	Program   interface{} `yaml:"program,omitempty"`
+	Program   interface{} `yaml:"program,omitempty"` // code block, see Code
 	Remarks   interface{} `yaml:"remarks,omitempty"`
 }
 type Test struct {
 	Desc   interface{} `yaml:"desc,omitempty"`
 	Expect interface{} `yaml:"expect,omitempty"`
 	Actual interface{} `yaml:"actual,omitempty"`
+	Result string      `yaml:"result,omitempty"` // Pass or Fail of the actual result
+	Pos    Position    `yaml:"-"`
 }
@@ -0,0 +1,67 @@
+package docb
+
+import "gopkg.in/yaml.v3"
+
+// Position is the location of the feature, screen, parameter, scenario or test in the spec file, blank if
+// unknown, e.g. the inherited screen. The items of the table are at the line of the table.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// setPositions sets the positions of the features and their items from the nodes decoded
+func setPositions(root *yaml.Node, spec *ProgSpec, origins map[*yaml.Node]string) {
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+	modules := findMappingValue(root, "modules")
+	if modules == nil || modules.Kind != yaml.SequenceNode || len(modules.Content) != len(spec.Modules) {
+		return
+	}
+	for i, mnode := range modules.Content {
+		features := findMappingValue(mnode, "features")
+		module := &spec.Modules[i]
+		if features == nil || features.Kind != yaml.SequenceNode || len(features.Content) != len(module.Features) {
+			continue
+		}
+		for j, fnode := range features.Content {
+			feature := &module.Features[j]
+			feature.Pos = nodePosition(fnode, origins)
+			itemPositions(fnode, "screens", len(feature.Screens), origins, func(k int, pos Position) { feature.Screens[k].Pos = pos })
+			itemPositions(fnode, "parameters", len(feature.Parameters), origins, func(k int, pos Position) { feature.Parameters[k].Pos = pos })
+			itemPositions(fnode, "scenarios", len(feature.Scenarios), origins, func(k int, pos Position) { feature.Scenarios[k].Pos = pos })
+			itemPositions(fnode, "tests", len(feature.Tests), origins, func(k int, pos Position) { feature.Tests[k].Pos = pos })
+		}
+	}
+}
+
+// itemPositions calls set with the position of each item of the list of the key, n is the number of the
+// items decoded
+func itemPositions(node *yaml.Node, key string, n int, origins map[*yaml.Node]string, set func(i int, pos Position)) {
+	list := findMappingValue(node, key)
+	if list == nil || list.Kind != yaml.SequenceNode || len(list.Content) != n {
+		return
+	}
+	for i, item := range list.Content {
+		set(i, nodePosition(item, origins))
+	}
+}
+
+// nodePosition returns the position of the id in the mapping, the mapping if no id
+func nodePosition(node *yaml.Node, origins map[*yaml.Node]string) Position {
+	file, ok := origins[node]
+	if !ok {
+		return Position{}
+	}
+	line, column := nodeLocation(node)
+	return Position{File: file, Line: line, Column: column}
+}
+
+// nodeLocation returns the line and column of the id in the mapping, the mapping if no id
+func nodeLocation(node *yaml.Node) (int, int) {
+	if id := findMappingValue(node, "id"); id != nil && id.Line > 0 {
+		return id.Line, id.Column
+	}
+	return node.Line, node.Column
+}
@@ -0,0 +1,114 @@
+package docb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+)
+
+// split modes
+const (
+	SplitFile   = "file"   // one document per input file
+	SplitModule = "module" // one document per module
+)
+
+// {name} is the input file name without extension, {module} is the module name, {index} is the
+// sequence number of the document, others are the variables, e.g. {module}-{version}.docx
+var patternVar = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// characters not allowed in the file name
+var invalidFileChars = regexp.MustCompile(`[\\/:*?"<>|]`)
+
+type loadedSpec struct {
+	file     string
+	data     *ProgSpec
+	vars     variables
+	appendix bool
+}
+
+// writeSplit writes one document per input file or module into the output directory
+func (b *Builder) writeSplit(specs []*loadedSpec) error {
+	if b.ofile == StdStream {
+		return eris.New("cannot split the documents to stdout")
+	}
+	if err := os.MkdirAll(b.ofile, os.ModePerm); err != nil {
+		return eris.Wrapf(err, "failed to create the output directory %s", b.ofile)
+	}
+
+	pattern := b.options.Pattern
+	if xstrings.IsBlank(pattern) {
+		pattern = "{name}.docx"
+		if b.options.Split == SplitModule {
+			pattern = "{module}.docx"
+		}
+	}
+
+	index := 0
+	written := map[string]string{}
+	write := func(spec *loadedSpec, module string) error {
+		index++
+		name, err := outputName(pattern, spec, module, index)
+		if err != nil {
+			return err
+		}
+		ofile := filepath.Join(b.ofile, name)
+		if source, ok := written[ofile]; ok {
+			return eris.Errorf("output file %s of %s is already written by %s, make the name pattern %s unique", ofile, spec.file, source, pattern)
+		}
+		written[ofile] = spec.file
+		return b.write(ofile, spec)
+	}
+
+	for _, spec := range specs {
+		if b.options.Split == SplitFile {
+			if err := write(spec, ""); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, module := range spec.data.Modules {
+			single := &loadedSpec{file: spec.file, data: &ProgSpec{Vars: spec.data.Vars, Modules: []Module{module}}, vars: spec.vars}
+			if err := write(single, module.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func outputName(pattern string, spec *loadedSpec, module string, index int) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(spec.file), filepath.Ext(spec.file))
+	if spec.file == StdStream {
+		name = "stdin"
+	}
+	if module == "" && len(spec.data.Modules) > 0 {
+		module = spec.data.Modules[0].Name
+	}
+
+	var undefined []string
+	result := patternVar.ReplaceAllStringFunc(pattern, func(m string) string {
+		key := m[1 : len(m)-1]
+		switch key {
+		case "name":
+			return name
+		case "module":
+			return module
+		case "index":
+			return fmt.Sprintf("%d", index)
+		}
+		if value, ok := spec.vars[key]; ok {
+			return value
+		}
+		undefined = append(undefined, m)
+		return m
+	})
+	if len(undefined) > 0 {
+		return "", eris.Errorf("undefined %s in the name pattern %s", strings.Join(undefined, ", "), pattern)
+	}
+	return invalidFileChars.ReplaceAllString(result, "_"), nil
+}
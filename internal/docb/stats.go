@@ -0,0 +1,194 @@
+package docb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+	"github.com/shomali11/util/xstrings"
+	"github.com/zrs01/pst/pkg/docx"
+)
+
+// ModuleStats is the statistics of the module, modules of the same name are counted together
+type ModuleStats struct {
+	Module              string  `json:"module"`
+	Features            int     `json:"features"`
+	Scenarios           int     `json:"scenarios"`
+	Tests               int     `json:"tests"`
+	TestsWithActual     int     `json:"testsWithActual"`
+	ScreensWithImage    int     `json:"screensWithImage"`
+	ScreensWithoutImage int     `json:"screensWithoutImage"`
+	EmptySections       int     `json:"emptySections"`
+	Completeness        float64 `json:"completeness"` // percentage
+	filled, expected    int
+}
+
+// StatsHeaders is the column headers of the statistics, in the order of Record
+var StatsHeaders = []string{"Module", "Features", "Scenarios", "Tests", "Tests with Actual", "Screens with Image", "Screens without Image", "Empty Sections", "Completeness"}
+
+// Record returns the values of the statistics in the order of StatsHeaders
+func (s ModuleStats) Record() []string {
+	return []string{
+		s.Module,
+		fmt.Sprint(s.Features), fmt.Sprint(s.Scenarios), fmt.Sprint(s.Tests), fmt.Sprint(s.TestsWithActual),
+		fmt.Sprint(s.ScreensWithImage), fmt.Sprint(s.ScreensWithoutImage), fmt.Sprint(s.EmptySections),
+		fmt.Sprintf("%.1f%%", s.Completeness),
+	}
+}
+
+// sections of the feature counted by EmptySections
+var statsSections = []string{"Desc", "Resources", "Screens", "Input", "Parameters", "Scenarios", "Tests"}
+
+// Stats counts the features of the specs by module, the completeness is the percentage of
+// the non-empty sections, tests with actual result and screens with image
+func Stats(specs []*ProgSpec) []ModuleStats {
+	result := []ModuleStats{}
+	index := map[string]int{}
+	for _, spec := range specs {
+		for _, module := range spec.Modules {
+			i, ok := index[module.Name]
+			if !ok {
+				i = len(result)
+				index[module.Name] = i
+				result = append(result, ModuleStats{Module: module.Name})
+			}
+			for _, feature := range module.Features {
+				result[i].add(&feature)
+			}
+		}
+	}
+	for i := range result {
+		result[i].complete()
+	}
+	return result
+}
+
+// TotalStats sums up the statistics of the modules
+func TotalStats(stats []ModuleStats) ModuleStats {
+	total := ModuleStats{Module: "Total"}
+	for _, s := range stats {
+		total.Features += s.Features
+		total.Scenarios += s.Scenarios
+		total.Tests += s.Tests
+		total.TestsWithActual += s.TestsWithActual
+		total.ScreensWithImage += s.ScreensWithImage
+		total.ScreensWithoutImage += s.ScreensWithoutImage
+		total.EmptySections += s.EmptySections
+		total.filled += s.filled
+		total.expected += s.expected
+	}
+	total.complete()
+	return total
+}
+
+func (s *ModuleStats) add(feature *Feature) {
+	filled := 0
+	s.Features++
+	s.Scenarios += len(feature.Scenarios)
+	s.Tests += len(feature.Tests)
+	for _, test := range feature.Tests {
+		if !isSectionEmpty(reflect.ValueOf(test.Actual)) {
+			s.TestsWithActual++
+			filled++
+		}
+	}
+	for _, screen := range feature.Screens {
+		if xstrings.IsNotBlank(screen.Image.File) {
+			s.ScreensWithImage++
+			filled++
+		} else {
+			s.ScreensWithoutImage++
+		}
+	}
+	value := reflect.ValueOf(*feature)
+	for _, name := range statsSections {
+		if isSectionEmpty(value.FieldByName(name)) {
+			s.EmptySections++
+		} else {
+			filled++
+		}
+	}
+	s.filled += filled
+	s.expected += len(statsSections) + len(feature.Tests) + len(feature.Screens)
+}
+
+func (s *ModuleStats) complete() {
+	if s.expected > 0 {
+		s.Completeness = float64(s.filled) * 100 / float64(s.expected)
+	}
+}
+
+// isSectionEmpty tells whether the value is nil, empty or blank text
+func isSectionEmpty(value reflect.Value) bool {
+	if !value.IsValid() {
+		return true
+	}
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return true
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return xstrings.IsBlank(value.String())
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Interface {
+			// list of text
+			return xstrings.IsBlank(strings.Join(ToStrArray(value.Interface()), ""))
+		}
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// writeSummary adds the statistics page of the specs at the front of the document
+func (b *Builder) writeSummary(docb *docx.DocumentBuilder, styles tableStyles, specs []*loadedSpec) {
+	data := []*ProgSpec{}
+	for _, spec := range specs {
+		data = append(data, spec.data)
+	}
+	stats := Stats(data)
+	stats = append(stats, TotalStats(stats))
+
+	bs := wml.ST_BorderSingle
+	bc := color.Auto
+	bt := measurement.Distance(0.5 * measurement.Point)
+
+	docb.AddParagraph(func(p *docx.ParagraphBuilder) {
+		p.SetStyle("Heading1").SetText("SUMMARY")
+	})
+	docb.AddTable(func(tb *docx.TableBuilder) {
+		tb.SetWidthPercent(100).SetBorders(func(b *docx.Borders) { b.SetBorderAll(bs, bc, bt) })
+		tb.AddRow(func(rb *docx.RowBuilder) {
+			for _, header := range StatsHeaders {
+				header := header
+				rb.AddCell(func(cb *docx.CellBuilder) { cb.SetText(header).SetStyle(styles.header) })
+			}
+		})
+		for i, s := range stats {
+			values := s.Record()
+			total := i == len(stats)-1
+			tb.AddRow(func(rb *docx.RowBuilder) {
+				for j, value := range values {
+					value := value
+					rb.AddCell(func(cb *docx.CellBuilder) {
+						cb.SetText(value).SetStyle(styles.body).DisableLinks()
+						if total {
+							cb.SetStyle(styles.label)
+						}
+						if j > 0 {
+							cb.SetAlignment(wml.ST_JcRight)
+						}
+					})
+				}
+			})
+		}
+	})
+	docb.AddParagraph(func(p *docx.ParagraphBuilder) {
+		p.SetPageBreak()
+	})
+}
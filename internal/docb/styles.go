@@ -0,0 +1,42 @@
+package docb
+
+import (
+	"baliance.com/gooxml/color"
+	"github.com/zrs01/pst/pkg/docx"
+)
+
+// paragraph styles of the tables, the styles of the --document template are used if it has them
+const (
+	styleTableHeader    = "PST Table Header"
+	styleTableSubheader = "PST Table Subheader"
+	styleLabel          = "PST Label"
+	styleBody           = "PST Body"
+)
+
+// tableStyles is the IDs of the styles in the document
+type tableStyles struct {
+	header    string // title of the table, e.g. Input Parameters:
+	subheader string // column headers and the titles of the items
+	label     string
+	body      string
+}
+
+// addTableStyles adds the styles missing in the document, the defaults are the font of the config and the
+// gray headers
+func addTableStyles(docb *docx.DocumentBuilder) tableStyles {
+	return tableStyles{
+		header: docb.AddStyle(styleTableHeader, func(sp *docx.StyleProperty) {
+			sp.SetBold().SetShading(color.FromHex("ced4da"))
+		}),
+		subheader: docb.AddStyle(styleTableSubheader, func(sp *docx.StyleProperty) {
+			sp.SetBold().SetShading(color.FromHex("e9ecef"))
+		}),
+		label: docb.AddStyle(styleLabel, func(sp *docx.StyleProperty) { sp.SetBold() }),
+		body:  docb.AddStyle(styleBody, func(sp *docx.StyleProperty) {}),
+	}
+}
+
+// AddStyles adds the styles of the tables to the document, e.g. the template to customize them in Word
+func AddStyles(docb *docx.DocumentBuilder) {
+	addTableStyles(docb)
+}
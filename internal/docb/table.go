@@ -0,0 +1,200 @@
+package docb
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"baliance.com/gooxml/spreadsheet"
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+	"gopkg.in/yaml.v3"
+)
+
+// tableKey is the key of the external table, e.g. parameters: {from: params.xlsx, sheet: UF010A}
+const tableKey = "from"
+
+// tableField is the field of the list loaded from the table
+type tableField struct {
+	name     string   // yaml key of the item
+	headers  []string // default headers of the column, case insensitive
+	required bool     // the row must have the value
+}
+
+// lists of the feature which can be loaded from the table, the default headers include the ones of the .xlsx output
+var tableFields = map[string][]tableField{
+	"parameters": {
+		{name: "field", headers: []string{"field", "fields"}, required: true},
+		{name: "data", headers: []string{"data", "data items"}},
+		{name: "io", headers: []string{"io", "i/o"}},
+		{name: "remarks", headers: []string{"remarks", "processing remarks"}},
+	},
+	"input": {
+		{name: "name", headers: []string{"name"}, required: true},
+		{name: "fields", headers: []string{"fields"}},
+		{name: "cons", headers: []string{"cons", "constraints"}},
+		{name: "remarks", headers: []string{"remarks"}},
+	},
+	"tests": {
+		{name: "desc", headers: []string{"desc", "description", "test description"}, required: true},
+		{name: "expect", headers: []string{"expect", "expected result"}},
+		{name: "actual", headers: []string{"actual", "actual result"}},
+		{name: "result", headers: []string{"result"}},
+	},
+}
+
+// tableRef is the reference to the table in the spec
+type tableRef struct {
+	From    string            `yaml:"from"`
+	Sheet   string            `yaml:"sheet,omitempty"`   // sheet of the .xlsx file, default is the first sheet
+	Columns map[string]string `yaml:"columns,omitempty"` // yaml key -> header of the column
+}
+
+// loadTable returns the list of the key loaded from the table referenced by the value, the rows of
+// other features are skipped if the table has the Feature ID column
+func (l *specLoader) loadTable(dir, key string, value, feature *yaml.Node) (*yaml.Node, error) {
+	var ref tableRef
+	if err := value.Decode(&ref); err != nil {
+		return nil, eris.Wrapf(err, "invalid table of %s at line %d in %s", key, value.Line, l.describe(""))
+	}
+	file := ref.From
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	l.files = append(l.files, file)
+
+	var headers []string
+	var rows []sheetRow
+	var err error
+	source := file
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		headers, rows, err = readCsv(file)
+	} else {
+		var wb *spreadsheet.Workbook
+		if wb, err = spreadsheet.Open(file); err == nil {
+			headers, rows, err = readSheet(wb, ref.Sheet)
+		}
+		if ref.Sheet != "" {
+			source = "sheet " + ref.Sheet + " of " + file
+		}
+	}
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the table %s of %s in %s", file, key, l.describe(""))
+	}
+
+	// yaml key -> header of the table
+	columns := map[string]string{}
+	for _, field := range tableFields[key] {
+		if header, ok := ref.Columns[field.name]; ok {
+			if columns[field.name] = findHeader(headers, header); columns[field.name] == "" {
+				return nil, eris.Errorf("column %s of %s is not found in %s", header, field.name, source)
+			}
+			continue
+		}
+		for _, header := range field.headers {
+			if columns[field.name] = findHeader(headers, header); columns[field.name] != "" {
+				break
+			}
+		}
+	}
+	for name := range ref.Columns {
+		if _, ok := columns[name]; !ok {
+			return nil, eris.Errorf("unknown column %s of %s at line %d in %s", name, key, value.Line, l.describe(""))
+		}
+	}
+
+	ids := []string{}
+	if id := findMappingValue(feature, "id"); id != nil {
+		var v interface{}
+		if err := id.Decode(&v); err == nil {
+			ids = ToStrArray(v)
+		}
+	}
+	idHeader := findHeader(headers, ColumnFeatureId)
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: value.Line, Column: value.Column}
+	for _, row := range rows {
+		if idHeader != "" && len(ids) > 0 && !xstrings.IsBlank(row.values[idHeader]) && strings.TrimSpace(row.values[idHeader]) != strings.Join(ids, " ") {
+			continue
+		}
+		item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: value.Line, Column: value.Column}
+		blank := true
+		for _, field := range tableFields[key] {
+			text := strings.TrimSpace(strings.ReplaceAll(row.values[columns[field.name]], "\r\n", "\n"))
+			if columns[field.name] == "" || text == "" {
+				continue
+			}
+			blank = false
+			item.Content = append(item.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.name}, cellNode(text))
+		}
+		if blank {
+			continue
+		}
+		for _, field := range tableFields[key] {
+			if field.required && findMappingValue(item, field.name) == nil {
+				return nil, eris.Errorf("row %d of %s: %s of %s is blank", row.number, source, field.name, key)
+			}
+		}
+		list.Content = append(list.Content, item)
+	}
+	l.markOrigin(list, l.origins[value])
+	return list, nil
+}
+
+// findHeader returns the header ignoring case, blank if not found
+func findHeader(headers []string, name string) string {
+	for _, header := range headers {
+		if strings.EqualFold(header, strings.TrimSpace(name)) {
+			return header
+		}
+	}
+	return ""
+}
+
+// cellNode returns the text of the cell, the multi-line text becomes the string array
+func cellNode(text string) *yaml.Node {
+	if !strings.Contains(text, "\n") {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: text}
+	}
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.TrimSpace(line)})
+		}
+	}
+	return node
+}
+
+// readCsv reads the csv file with the headers in the first row
+func readCsv(file string) ([]string, []sheetRow, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, []sheetRow{}, nil
+	}
+	headers := []string{}
+	for _, header := range records[0] {
+		headers = append(headers, strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	}
+	rows := []sheetRow{}
+	for i, record := range records[1:] {
+		values := map[string]string{}
+		for j, value := range record {
+			if j < len(headers) && headers[j] != "" {
+				values[headers[j]] = value
+			}
+		}
+		rows = append(rows, sheetRow{number: i + 2, values: values})
+	}
+	return headers, rows, nil
+}
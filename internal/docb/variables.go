@@ -0,0 +1,108 @@
+package docb
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// ${name} is replaced by the value of the variable, $${name} is kept as literal ${name}
+var varPattern = regexp.MustCompile(`\$?\$\{([A-Za-z0-9_.-]+)\}`)
+
+type variables map[string]string
+
+// newVariables merges the variables, the later one overrides the former
+func newVariables(vars ...map[string]string) variables {
+	v := variables{}
+	for _, m := range vars {
+		for key, value := range m {
+			v[key] = value
+		}
+	}
+	return v
+}
+
+// apply substitutes the variables in every string of the module and its features
+func (v variables) apply(module *Module) error {
+	undefined := map[string]bool{}
+	module.Name = v.expand(module.Name, undefined)
+	if len(undefined) > 0 {
+		return eris.Errorf("undefined variable %s in module %s", v.names(undefined), module.Name)
+	}
+	for i := range module.Features {
+		v.substitute(reflect.ValueOf(&module.Features[i]).Elem(), undefined)
+		if len(undefined) > 0 {
+			return eris.Errorf("undefined variable %s in feature %s", v.names(undefined), strings.Join(ToStrArray(module.Features[i].Id), " "))
+		}
+	}
+	return nil
+}
+
+func (v variables) substitute(value reflect.Value, undefined map[string]bool) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(v.expand(value.String(), undefined))
+	case reflect.Struct:
+		if value.Type() == reflect.TypeOf(Position{}) {
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			v.substitute(value.Field(i), undefined)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.substitute(value.Index(i), undefined)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			if key.Kind() == reflect.String && key.String() == codeKey {
+				// the code is kept as written, e.g. ${name} of JavaScript
+				continue
+			}
+			elem := reflect.New(value.Type().Elem()).Elem()
+			elem.Set(value.MapIndex(key))
+			v.substitute(elem, undefined)
+			value.SetMapIndex(key, elem)
+		}
+	case reflect.Interface, reflect.Ptr:
+		if value.IsNil() {
+			return
+		}
+		if value.Kind() == reflect.Ptr {
+			v.substitute(value.Elem(), undefined)
+			return
+		}
+		// the value inside interface is not addressable, substitute on a copy
+		elem := reflect.New(value.Elem().Type()).Elem()
+		elem.Set(value.Elem())
+		v.substitute(elem, undefined)
+		value.Set(elem)
+	}
+}
+
+func (v variables) expand(s string, undefined map[string]bool) string {
+	return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if strings.HasPrefix(m, "$$") {
+			return m[1:]
+		}
+		name := m[2 : len(m)-1]
+		value, ok := v[name]
+		if !ok {
+			undefined[name] = true
+			return m
+		}
+		return value
+	})
+}
+
+func (v variables) names(undefined map[string]bool) string {
+	names := []string{}
+	for name := range undefined {
+		names = append(names, "${"+name+"}")
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
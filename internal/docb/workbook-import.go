@@ -0,0 +1,414 @@
+package docb
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"baliance.com/gooxml/spreadsheet"
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+	"gopkg.in/yaml.v3"
+)
+
+// sheetRow is the row of the sheet, the values are by the header of the first row
+type sheetRow struct {
+	number int
+	values map[string]string
+}
+
+// readSheet reads the sheet by name, the first sheet if the name is blank. Returns the headers as well.
+func readSheet(wb *spreadsheet.Workbook, name string) ([]string, []sheetRow, error) {
+	sheets := wb.Sheets()
+	var sheet *spreadsheet.Sheet
+	for i := range sheets {
+		if name == "" || strings.EqualFold(sheets[i].Name(), name) {
+			sheet = &sheets[i]
+			break
+		}
+	}
+	if sheet == nil {
+		return nil, nil, eris.Errorf("no such sheet %s", name)
+	}
+
+	rows := []sheetRow{}
+	headers := []string{}
+	columns := map[string]string{} // column -> header
+	for i, row := range sheet.Rows() {
+		values := map[string]string{}
+		for _, cell := range row.Cells() {
+			column := strings.TrimRight(cell.Reference(), "0123456789")
+			if i == 0 {
+				header := strings.TrimSpace(cell.GetString())
+				columns[column] = header
+				headers = append(headers, header)
+			} else if header, ok := columns[column]; ok && header != "" {
+				values[header] = cell.GetString()
+			}
+		}
+		if i > 0 {
+			rows = append(rows, sheetRow{number: int(row.RowNumber()), values: values})
+		}
+	}
+	return headers, rows, nil
+}
+
+// testResult is the actual result of the test read from the workbook
+type testResult struct {
+	row    int
+	testNo int
+	pos    Position
+	fields []testField
+}
+
+// testField is the text of the key of the test
+type testField struct {
+	key, text string
+}
+
+// ImportActualResults updates the actual results and the results of the tests in the input files by the tests
+// sheet written by the .xlsx output, the features are found by the ID and the test by the number. Only the
+// tests changed are updated, they must be defined in the tests list of the feature, not inherited or read
+// from a table. Returns the files changed.
+func ImportActualResults(xfile string, specs []Spec) ([]string, error) {
+	wb, err := spreadsheet.Open(xfile)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to open the workbook %s", xfile)
+	}
+	headers, rows, err := readSheet(wb, SheetTests)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the workbook %s", xfile)
+	}
+	actualHeader, resultHeader := findHeader(headers, ColumnActual), findHeader(headers, ColumnResult)
+
+	features := map[string]*Feature{}
+	for _, spec := range specs {
+		for _, module := range spec.Data.Modules {
+			for i := range module.Features {
+				feature := &module.Features[i]
+				id := strings.Join(ToStrArray(feature.Id), " ")
+				if _, ok := features[id]; !ok && feature.Pos != (Position{}) {
+					features[id] = feature
+				}
+			}
+		}
+	}
+
+	// file -> results
+	results := map[string][]testResult{}
+	files := []string{}
+	for _, row := range rows {
+		id := strings.TrimSpace(row.values[ColumnFeatureId])
+		if id == "" {
+			continue
+		}
+		feature, ok := features[id]
+		if !ok {
+			return nil, eris.Errorf("row %d of sheet %s: no such feature %s", row.number, SheetTests, id)
+		}
+		testNo, err := strconv.Atoi(strings.TrimSpace(row.values[ColumnTestNo]))
+		if err != nil || testNo < 1 {
+			return nil, eris.Errorf("row %d of sheet %s: invalid test number %s", row.number, SheetTests, row.values[ColumnTestNo])
+		}
+		if testNo > len(feature.Tests) {
+			return nil, eris.Errorf("row %d of sheet %s: test %d is not defined in feature %s", row.number, SheetTests, testNo, id)
+		}
+		test := feature.Tests[testNo-1]
+
+		fields := []testField{}
+		if actualHeader != "" {
+			actual := strings.TrimRight(strings.ReplaceAll(row.values[actualHeader], "\r\n", "\n"), "\n")
+			if actual != strings.Join(ToStrArray(test.Actual), "\n") {
+				fields = append(fields, testField{key: "actual", text: actual})
+			}
+		}
+		if resultHeader != "" {
+			result, ok := parseTestResult(row.values[resultHeader])
+			if !ok {
+				return nil, eris.Errorf("row %d of sheet %s: invalid result %s, must be one of %s", row.number, SheetTests, row.values[resultHeader], strings.Join(testResults, ", "))
+			}
+			if result != test.Result {
+				fields = append(fields, testField{key: "result", text: result})
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		if test.Pos == (Position{}) {
+			return nil, eris.Errorf("row %d of sheet %s: test %d of feature %s is inherited, update the feature defining it", row.number, SheetTests, testNo, id)
+		}
+		if _, ok := results[test.Pos.File]; !ok {
+			files = append(files, test.Pos.File)
+		}
+		results[test.Pos.File] = append(results[test.Pos.File], testResult{row: row.number, testNo: testNo, pos: test.Pos, fields: fields})
+	}
+
+	changed := []string{}
+	for _, file := range files {
+		ok, err := updateActualResults(file, results[file])
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+// parseTestResult returns the result of the test ignoring case, blank is allowed
+func parseTestResult(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", true
+	}
+	for _, result := range testResults {
+		if strings.EqualFold(result, text) {
+			return result, true
+		}
+	}
+	return "", false
+}
+
+// updateActualResults sets the fields of the tests found by the positions, returns whether the file is changed.
+// Only the text of the fields is edited to keep the format and comments of the file.
+func updateActualResults(file string, results []testResult) (bool, error) {
+	if formatOf(file) != FormatYAML {
+		return false, eris.Errorf("failed to update %s, only the yaml file is supported", file)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return false, eris.Wrapf(err, "failed to read the file %s", file)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return false, eris.Wrapf(err, "failed to unmarshal the file %s", file)
+	}
+	lines := strings.Split(string(content), "\n")
+
+	edits := []textEdit{}
+	for _, result := range results {
+		test := findTestNode(&root, result.pos)
+		if test == nil {
+			return false, eris.Errorf("row %d of sheet %s: test %d at %s:%d is not in a tests list, e.g. read from a table", result.row, SheetTests, result.testNo, file, result.pos.Line)
+		}
+		found, err := testEdits(lines, test, result.fields)
+		if err != nil {
+			return false, eris.Wrapf(err, "row %d of sheet %s: failed to update test %d at %s:%d", result.row, SheetTests, result.testNo, file, result.pos.Line)
+		}
+		edits = append(edits, found...)
+	}
+	if len(edits) == 0 {
+		return false, nil
+	}
+
+	if err := os.WriteFile(file, []byte(strings.Join(applyEdits(lines, edits), "\n")), 0644); err != nil {
+		return false, eris.Wrapf(err, "failed to write the file %s", file)
+	}
+	return true, nil
+}
+
+// findTestNode returns the test at the position, see nodePosition. The test is the item of the tests or tests+
+// list, or the item of the file included by the list.
+func findTestNode(root *yaml.Node, pos Position) *yaml.Node {
+	candidates := []*yaml.Node{}
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if key := node.Content[i].Value; (key == "tests" || key == "tests+") && node.Content[i+1].Kind == yaml.SequenceNode {
+					candidates = append(candidates, node.Content[i+1].Content...)
+				}
+			}
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(root)
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		if top := root.Content[0]; top.Kind == yaml.SequenceNode {
+			candidates = append(candidates, top.Content...)
+		} else {
+			candidates = append(candidates, top)
+		}
+	}
+
+	for _, node := range candidates {
+		if node.Kind != yaml.MappingNode || findMappingValue(node, tableKey) != nil {
+			continue
+		}
+		if line, column := nodeLocation(node); line == pos.Line && column == pos.Column {
+			return node
+		}
+	}
+	return nil
+}
+
+// textEdit replaces the text between the positions, the lines are zero-based and the columns are byte offsets
+type textEdit struct {
+	line, col, endLine, endCol int
+	text                       string
+}
+
+// applyEdits applies the edits not overlapping each other
+func applyEdits(lines []string, edits []textEdit) []string {
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].line != edits[j].line {
+			return edits[i].line > edits[j].line
+		}
+		return edits[i].col > edits[j].col
+	})
+	for _, e := range edits {
+		replaced := strings.Split(lines[e.line][:e.col]+e.text+lines[e.endLine][e.endCol:], "\n")
+		lines = append(lines[:e.line], append(replaced, lines[e.endLine+1:]...)...)
+	}
+	return lines
+}
+
+// testEdits returns the edits setting the fields of the test, the fields not changed are skipped
+func testEdits(lines []string, test *yaml.Node, fields []testField) ([]textEdit, error) {
+	if test.Kind != yaml.MappingNode || len(test.Content) == 0 {
+		return nil, eris.New("test is not a mapping")
+	}
+	flow := test.Style&yaml.FlowStyle != 0
+	indent := test.Content[0].Column - 1
+
+	edits := []textEdit{}
+	added := ""
+	for _, field := range fields {
+		text := strings.TrimRight(strings.ReplaceAll(field.text, "\r\n", "\n"), "\n")
+		quoted := strconv.Quote(text)
+		value := findMappingValue(test, field.key)
+		if value == nil {
+			if xstrings.IsBlank(text) {
+				continue
+			}
+			if flow {
+				added += ", " + field.key + ": " + quoted
+			} else {
+				added += "\n" + strings.Repeat(" ", indent) + field.key + ": " + quoted
+			}
+			continue
+		}
+		if value.Kind == yaml.ScalarNode && value.Value == text {
+			continue
+		}
+		if value.Kind == yaml.SequenceNode && strings.Join(nodeStrings(value), "\n") == text {
+			continue
+		}
+		if value.Kind != yaml.ScalarNode {
+			return nil, eris.Errorf("%s at line %d is not text", field.key, value.Line)
+		}
+		line := value.Line - 1
+		col := byteColumn(lines[line], value.Column)
+		if end := endOfLines(lines, line, indent); !flow && end > line {
+			// block or multi-line scalar
+			edits = append(edits, textEdit{line: line, col: col, endLine: end, endCol: len(strings.TrimRight(lines[end], "\r")), text: quoted})
+			continue
+		}
+		endCol, ok := scalarEnd(lines[line], col, flow)
+		if !ok {
+			return nil, eris.Errorf("failed to find the end of %s at line %d", field.key, value.Line)
+		}
+		edits = append(edits, textEdit{line: line, col: col, endLine: line, endCol: endCol, text: quoted})
+	}
+	if added == "" {
+		return edits, nil
+	}
+
+	// the fields added are after the last value
+	last := test.Content[len(test.Content)-1]
+	line := last.Line - 1
+	if flow {
+		if last.Kind != yaml.ScalarNode {
+			return nil, eris.Errorf("failed to add the fields after line %d", last.Line)
+		}
+		endCol, ok := scalarEnd(lines[line], byteColumn(lines[line], last.Column), true)
+		if !ok {
+			return nil, eris.Errorf("failed to add the fields after line %d", last.Line)
+		}
+		return append(edits, textEdit{line: line, col: endCol, endLine: line, endCol: endCol, text: added}), nil
+	}
+	end := endOfLines(lines, line, indent)
+	endCol := len(strings.TrimRight(lines[end], "\r"))
+	return append(edits, textEdit{line: end, col: endCol, endLine: end, endCol: endCol, text: added}), nil
+}
+
+// endOfLines returns the last line of the value starting at the line, the following lines indented more than
+// the key belong to the value
+func endOfLines(lines []string, line, indent int) int {
+	end := line
+	for i := line + 1; i < len(lines); i++ {
+		text := strings.TrimRight(lines[i], "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(text)-len(strings.TrimLeft(text, " ")) <= indent {
+			break
+		}
+		end = i
+	}
+	return end
+}
+
+// scalarEnd returns the byte offset after the single-line scalar starting at the offset
+func scalarEnd(line string, start int, flow bool) (int, bool) {
+	line = strings.TrimRight(line, "\r")
+	if start >= len(line) {
+		return start, true
+	}
+	switch line[start] {
+	case '"':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				return i + 1, true
+			}
+		}
+		return 0, false
+	case '\'':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1, true
+			}
+		}
+		return 0, false
+	}
+	end := len(line)
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		if (flow && (c == ',' || c == '}' || c == ']')) || (c == '#' && i > start && (line[i-1] == ' ' || line[i-1] == '\t')) {
+			end = i
+			break
+		}
+	}
+	return len(strings.TrimRight(line[:end], " \t")), true
+}
+
+// byteColumn converts the 1-based character column of yaml to the byte offset of the line
+func byteColumn(line string, column int) int {
+	n := 0
+	for i := range line {
+		if n == column-1 {
+			return i
+		}
+		n++
+	}
+	return len(line)
+}
+
+// nodeStrings returns the values of the sequence of scalars
+func nodeStrings(node *yaml.Node) []string {
+	values := []string{}
+	for _, child := range node.Content {
+		values = append(values, child.Value)
+	}
+	return values
+}
@@ -0,0 +1,147 @@
+package docb
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestTestEdits(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		fields []testField
+		want   string // the spec after the edits, or the error
+	}{
+		{
+			name:   "replace keeps comment",
+			spec:   "tests:\n  - desc: a\n    actual: old # note\n  - desc: b\n",
+			fields: []testField{{"actual", "new"}},
+			want:   "tests:\n  - desc: a\n    actual: \"new\" # note\n  - desc: b\n",
+		},
+		{
+			name:   "add after last value",
+			spec:   "tests:\n  - desc: a\n    expect: |\n      line 1\n      line 2\n  - desc: b\n",
+			fields: []testField{{"actual", "done"}, {"result", "Pass"}},
+			want:   "tests:\n  - desc: a\n    expect: |\n      line 1\n      line 2\n    actual: \"done\"\n    result: \"Pass\"\n  - desc: b\n",
+		},
+		{
+			name:   "replace multi-line",
+			spec:   "tests:\n  - desc: a\n    actual: |\n      old 1\n      old 2\n    result: Fail\n",
+			fields: []testField{{"actual", "new 1\r\nnew 2\r\n"}, {"result", "Pass"}},
+			want:   "tests:\n  - desc: a\n    actual: \"new 1\\nnew 2\"\n    result: \"Pass\"\n",
+		},
+		{
+			name:   "flow replace and add",
+			spec:   "tests:\n  - { desc: a, actual: 'old' } # note\n",
+			fields: []testField{{"actual", "it's new"}, {"result", "Fail"}},
+			want:   "tests:\n  - { desc: a, actual: \"it's new\", result: \"Fail\" } # note\n",
+		},
+		{
+			name:   "unchanged",
+			spec:   "tests:\n  - desc: a\n    actual:\n      - line 1\n      - line 2\n",
+			fields: []testField{{"actual", "line 1\nline 2"}, {"result", ""}},
+			want:   "tests:\n  - desc: a\n    actual:\n      - line 1\n      - line 2\n",
+		},
+		{
+			name:   "cleared",
+			spec:   "tests:\n  - desc: a\n    actual: old\n",
+			fields: []testField{{"actual", ""}},
+			want:   "tests:\n  - desc: a\n    actual: \"\"\n",
+		},
+		{
+			name:   "not text",
+			spec:   "tests:\n  - desc: a\n    actual: {text: old}\n",
+			fields: []testField{{"actual", "new"}},
+			want:   "error: actual at line 3 is not text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			if err := yaml.Unmarshal([]byte(tt.spec), &root); err != nil {
+				t.Fatal(err)
+			}
+			test := findMappingValue(root.Content[0], "tests").Content[0]
+			lines := strings.Split(tt.spec, "\n")
+			edits, err := testEdits(lines, test, tt.fields)
+			if err != nil {
+				if !strings.HasPrefix(tt.want, "error: ") || !strings.Contains(err.Error(), strings.TrimPrefix(tt.want, "error: ")) {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if got := strings.Join(applyEdits(lines, edits), "\n"); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTestNode(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.yml": `modules:
+  - name: M
+    features:
+      - id: A
+        tests: [{desc: a1}, {desc: a2}]
+      - id: B
+        extends: A
+        tests+:
+          - desc: b1
+      - id: C
+        tests: !include tests.yml
+      - id: D
+        tests:
+          from: tests.csv
+`,
+		"tests.yml": "- desc: c1\n",
+		"tests.csv": "desc\nd1\n",
+	})
+	specs, err := Load("", filepath.Join(dir, "spec.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string][]string{}
+	for _, feature := range specs[0].Data.Modules[0].Features {
+		id := feature.Id.(string)
+		for _, test := range feature.Tests {
+			desc := test.Desc.(string)
+			if test.Pos == (Position{}) {
+				got[id] = append(got[id], desc+" inherited")
+				continue
+			}
+			content, err := os.ReadFile(test.Pos.File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var root yaml.Node
+			if err := yaml.Unmarshal(content, &root); err != nil {
+				t.Fatal(err)
+			}
+			node := findTestNode(&root, test.Pos)
+			if node == nil {
+				got[id] = append(got[id], desc+" not found")
+				continue
+			}
+			if value := findMappingValue(node, "desc"); value == nil || value.Value != desc {
+				t.Errorf("test %s of %s found another test", desc, id)
+			}
+			got[id] = append(got[id], desc+" in "+filepath.Base(test.Pos.File))
+		}
+	}
+	want := map[string][]string{
+		"A": {"a1 in spec.yml", "a2 in spec.yml"},
+		"B": {"a1 inherited", "a2 inherited", "b1 in spec.yml"},
+		"C": {"c1 in tests.yml"},
+		"D": {"d1 not found"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
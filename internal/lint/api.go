@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/openapi"
+)
+
+// checkApiDrift reports the features whose operation is removed from the OpenAPI document or whose
+// parameters differ from the ones generated by pst import-openapi
+func checkApiDrift(c *context) {
+	file := c.cfg.Lint.OpenAPI
+	if file == "" {
+		return
+	}
+	var doc *openapi.Document
+	failed := false // the error of the document is reported once
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		if feature.Api == nil || failed {
+			return
+		}
+		if doc == nil {
+			var err error
+			if doc, err = openapi.Load(file); err != nil {
+				c.report(docb.Position{File: file}, "%s", eris.ToString(err, false))
+				failed = true
+				return
+			}
+		}
+		op := doc.Find(feature.Api.Method, feature.Api.Path)
+		if op == nil {
+			c.report(feature.Pos, "%s of feature %s is not in %s", feature.Api, featureId(feature), file)
+			return
+		}
+
+		generated := doc.Feature(*op)
+		expected := map[string]string{} // field -> io
+		for _, p := range generated.Parameters {
+			expected[paramName(p)] = strings.Join(docb.ToStrArray(p.IO), "")
+		}
+		missing, extra, changed := []string{}, []string{}, []string{}
+		actual := map[string]bool{}
+		for _, p := range feature.Parameters {
+			name := paramName(p)
+			actual[name] = true
+			io, ok := expected[name]
+			if !ok {
+				extra = append(extra, name)
+			} else if got := strings.Join(docb.ToStrArray(p.IO), ""); got != io {
+				changed = append(changed, name+" ("+got+" instead of "+io+")")
+			}
+		}
+		for _, p := range generated.Parameters {
+			if name := paramName(p); !actual[name] {
+				missing = append(missing, name)
+			}
+		}
+		details := []string{}
+		for _, d := range []struct {
+			label string
+			names []string
+		}{{"missing", missing}, {"not in the API", extra}, {"io changed", changed}} {
+			if len(d.names) > 0 {
+				details = append(details, d.label+": "+strings.Join(d.names, ", "))
+			}
+		}
+		if len(details) > 0 {
+			c.report(feature.Pos, "parameters of feature %s differ from %s in %s, %s", featureId(feature), feature.Api, file, strings.Join(details, "; "))
+		}
+	})
+}
+
+func paramName(p docb.Parameter) string {
+	return strings.TrimSpace(strings.Join(docb.ToStrArray(p.Field), " "))
+}
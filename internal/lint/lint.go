@@ -0,0 +1,215 @@
+// checks of the spec beyond loading, e.g. duplicated or unresolved IDs
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/thoas/go-funk"
+	"github.com/zrs01/pst/internal/config"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+// severities of the issue, off disables the rule
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+var severities = []string{SeverityError, SeverityWarning, SeverityInfo, SeverityOff}
+
+// Issue is the problem found by the rule
+type Issue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Message  string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s [%s]", i.File, i.Line, i.Severity, i.Message, i.Rule)
+}
+
+// Options are the optional settings of Check
+type Options struct {
+	Overlay map[string][]byte // content used instead of reading the file, see docb.Options
+}
+
+// context is shared by the rules
+type context struct {
+	specs    []docb.Spec
+	cfg      *config.Config
+	index    *docb.IdIndex
+	patterns map[string]*regexp.Regexp // module name -> feature ID pattern
+	rule     *Rule                     // the running rule
+	severity string                    // severity of the running rule
+	issues   []Issue
+}
+
+func (c *context) report(pos docb.Position, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Rule:     c.rule.Name,
+		Severity: c.severity,
+		File:     pos.File,
+		Line:     pos.Line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// features calls the function with every feature and its module
+func (c *context) features(fn func(module *docb.Module, feature *docb.Feature)) {
+	for _, spec := range c.specs {
+		for i := range spec.Data.Modules {
+			module := &spec.Data.Modules[i]
+			for j := range module.Features {
+				fn(module, &module.Features[j])
+			}
+		}
+	}
+}
+
+// Check runs the enabled rules on the specs loaded by docb.Load
+func Check(specs []docb.Spec, cfg *config.Config, opts ...Options) ([]Issue, error) {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	c := &context{specs: specs, cfg: cfg, patterns: map[string]*regexp.Regexp{}}
+	for module, pattern := range cfg.Lint.IdPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid ID pattern of module %s", module)
+		}
+		c.patterns[module] = re
+	}
+	for name, severity := range cfg.Lint.Rules {
+		if FindRule(name) == nil {
+			return nil, eris.Errorf("unknown lint rule %s", name)
+		}
+		if !funk.ContainsString(severities, severity) {
+			return nil, eris.Errorf("invalid severity %s of lint rule %s, must be one of %s", severity, name, strings.Join(severities, ", "))
+		}
+	}
+	data := []*docb.ProgSpec{}
+	for _, spec := range specs {
+		data = append(data, spec.Data)
+	}
+	patterns := []*regexp.Regexp{}
+	for _, re := range c.patterns {
+		patterns = append(patterns, re)
+	}
+	c.index = docb.NewIdIndex(data, patterns...)
+
+	for _, rule := range rules {
+		c.rule, c.severity = rule, rule.Severity
+		if severity, ok := cfg.Lint.Rules[rule.Name]; ok {
+			c.severity = severity
+		}
+		if c.severity == SeverityOff {
+			continue
+		}
+		rule.check(c)
+	}
+
+	issues := newSuppressions(opt.Overlay).filter(c.issues)
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// CountErrors returns the number of the issues which are errors, the warnings and infos don't fail the lint
+func CountErrors(issues []Issue) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// "# pst:ignore" ignores all rules, "# pst:ignore rule1, rule2" ignores the listed rules
+var ignoreComment = regexp.MustCompile(`#\s*pst:ignore\b([^#]*)`)
+
+// suppressions are the ignore comments of the input files, which apply to the same line,
+// or the next line if the comment is the only content of the line
+type suppressions struct {
+	overlay map[string][]byte
+	files   map[string][]string // file -> lines
+}
+
+func newSuppressions(overlay map[string][]byte) *suppressions {
+	return &suppressions{overlay: overlay, files: map[string][]string{}}
+}
+
+func (s *suppressions) filter(issues []Issue) []Issue {
+	result := []Issue{}
+	for _, issue := range issues {
+		if !s.ignored(issue) {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+func (s *suppressions) ignored(issue Issue) bool {
+	if issue.File == "" || issue.File == docb.StdStream || issue.Line <= 0 {
+		return false
+	}
+	lines := s.lines(issue.File)
+	if issue.Line <= len(lines) && ignores(lines[issue.Line-1], issue.Rule) {
+		return true
+	}
+	if issue.Line >= 2 && issue.Line-1 <= len(lines) {
+		previous := lines[issue.Line-2]
+		if strings.HasPrefix(strings.TrimSpace(previous), "#") && ignores(previous, issue.Rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *suppressions) lines(file string) []string {
+	if lines, ok := s.files[file]; ok {
+		return lines
+	}
+	content, ok := s.overlay[file]
+	if !ok {
+		if path, err := filepath.Abs(file); err == nil {
+			content, ok = s.overlay[path]
+		}
+	}
+	if !ok {
+		var err error
+		if content, err = os.ReadFile(file); err != nil {
+			// the issue is kept if the file cannot be read
+			content = nil
+		}
+	}
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	s.files[file] = lines
+	return lines
+}
+
+// ignores tells whether the line has the comment ignoring the rule
+func ignores(line, rule string) bool {
+	m := ignoreComment.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	names := strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	return len(names) == 0 || funk.ContainsString(names, rule)
+}
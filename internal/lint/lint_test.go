@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zrs01/pst/internal/config"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+const lintSpec = `modules:
+  - name: M
+    features:
+      - id: UF010A
+        name: Base
+        desc: the description of the base feature
+        parameters:
+          - { field: a, io: I }
+          - { field: b, io: X }
+          # pst:ignore param-io
+          - { field: c, io: Y }
+        scenarios:
+          - name: s1
+            desc: [When the user saves]
+          - name: s2
+            desc: [Then it fails, see UF099A]
+        tests:
+          - desc: saved
+          - desc: TBD
+      - id: UF011A
+        extends: UF010A
+        desc: the description of the child feature
+`
+
+func TestCheckPositions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "spec.yml")
+	if err := os.WriteFile(file, []byte(lintSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	specs, err := docb.Load("", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.NewConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Lint.Rules = map[string]string{"no-placeholder": SeverityWarning}
+	issues, err := Check(specs, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type found struct {
+		rule string
+		line int
+	}
+	got := []found{}
+	for _, issue := range issues {
+		got = append(got, found{issue.Rule, issue.Line})
+	}
+	want := []found{
+		{"param-io", 9},              // the parameter
+		{"unresolved-reference", 15}, // the text of the scenario
+		{"scenario-start", 15},       // the scenario
+		{"no-placeholder", 19},       // the test
+		{"unresolved-reference", 20}, // inherited by UF011A, on its id
+		{"param-io", 20},
+		{"param-io", 20}, // the comment of the item doesn't apply to the inherited one
+		{"scenario-start", 20},
+		{"no-placeholder", 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCountErrors(t *testing.T) {
+	issues := []Issue{{Severity: SeverityError}, {Severity: SeverityWarning}, {Severity: SeverityInfo}, {Severity: SeverityError}}
+	if got := CountErrors(issues); got != 2 {
+		t.Errorf("got %d errors, want 2", got)
+	}
+	if got := CountErrors(nil); got != 0 {
+		t.Errorf("got %d errors, want 0", got)
+	}
+}
+
+const driftOpenAPI = `openapi: 3.0.0
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, schema: {type: integer}}
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {properties: {name: {type: string}}}
+    put:
+      parameters:
+        - {name: id, in: path, schema: {type: integer}}
+        - {name: lang, in: query, schema: {type: string}}
+      responses:
+        "204": {description: done}
+`
+
+const driftSpec = `modules:
+  - name: M
+    features:
+      - id: A
+        api: {method: get, path: "/users/{id}"}
+        parameters:
+          - { field: id, io: I }
+          - { field: name, io: O }
+      - id: B
+        api: {method: PUT, path: "/users/{id}"}
+        parameters:
+          - { field: id, io: O }
+          - { field: x, io: I }
+      - id: C
+        api: {method: DELETE, path: "/users/{id}"}
+      - id: D
+`
+
+func TestCheckApiDrift(t *testing.T) {
+	dir := t.TempDir()
+	spec, api := filepath.Join(dir, "spec.yml"), filepath.Join(dir, "openapi.yaml")
+	for file, content := range map[string]string{spec: driftSpec, api: driftOpenAPI} {
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	specs, err := docb.Load("", spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		openapi string
+		want    []string
+	}{
+		{
+			name:    "drift",
+			openapi: api,
+			want: []string{
+				"parameters of feature B differ from PUT /users/{id} in " + api + ", missing: lang; not in the API: x; io changed: id (O instead of I)",
+				"DELETE /users/{id} of feature C is not in " + api,
+			},
+		},
+		{
+			name:    "document reported once",
+			openapi: filepath.Join(dir, "missing.yaml"),
+			want:    []string{"failed to read the OpenAPI document " + filepath.Join(dir, "missing.yaml")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.NewConfig("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Lint.OpenAPI = tt.openapi
+			issues, err := Check(specs, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, issue := range issues {
+				if issue.Rule == "api-drift" {
+					got = append(got, issue.Message)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("got %q, want %q", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
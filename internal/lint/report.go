@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/rotisserie/eris"
+)
+
+// output formats of Write
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Write writes the issues in the format
+func Write(w io.Writer, format string, issues []Issue) error {
+	switch format {
+	case "", FormatText:
+		for _, issue := range issues {
+			if _, err := fmt.Fprintln(w, issue); err != nil {
+				return eris.Wrap(err, "failed to write the issues")
+			}
+		}
+		return nil
+	case FormatJSON:
+		return writeJSON(w, issues)
+	case FormatSARIF:
+		return writeJSON(w, newSarif(issues))
+	}
+	return eris.Errorf("unknown output format %s, must be one of %s, %s or %s", format, FormatText, FormatJSON, FormatSARIF)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return eris.Wrap(err, "failed to write the issues")
+	}
+	return nil
+}
+
+// subset of SARIF 2.1.0 for the code scanning tools
+type sarif struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	DefaultConfig    struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func newSarif(issues []Issue) sarif {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "pst"
+	for _, rule := range rules {
+		r := sarifRule{Id: rule.Name, ShortDescription: sarifMessage{Text: rule.Description}}
+		r.DefaultConfig.Level = sarifLevel(rule.Severity)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, r)
+	}
+	for _, issue := range issues {
+		result := sarifResult{RuleId: issue.Rule, Level: sarifLevel(issue.Severity), Message: sarifMessage{Text: issue.Message}}
+		if issue.File != "" {
+			var location sarifLocation
+			location.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(issue.File)
+			if issue.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line}
+			}
+			result.Locations = append(result.Locations, location)
+		}
+		run.Results = append(run.Results, result)
+	}
+	return sarif{Schema: "https://json.schemastore.org/sarif-2.1.0.json", Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	}
+	return "none"
+}
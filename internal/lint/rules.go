@@ -0,0 +1,252 @@
+package lint
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/thoas/go-funk"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+// Rule is the check of the specs, the severity can be changed by lint.rules of the config
+type Rule struct {
+	Name        string
+	Description string
+	Severity    string // default severity
+	check       func(c *context)
+}
+
+// rules are run in the order
+var rules = []*Rule{
+	{Name: "duplicate-id", Description: "feature and screen IDs are unique across all inputs", Severity: SeverityError, check: checkDuplicateIds},
+	{Name: "id-format", Description: "feature IDs match lint.idpatterns of the module", Severity: SeverityError, check: checkIdFormat},
+	{Name: "unresolved-reference", Description: "IDs mentioned in the text are features or screens", Severity: SeverityWarning, check: checkReferences},
+	{Name: "scenario-required", Description: "every feature has at least one scenario", Severity: SeverityWarning, check: checkScenarioRequired},
+	{Name: "test-required", Description: "every feature has at least one test", Severity: SeverityWarning, check: checkTestRequired},
+	{Name: "desc-min-words", Description: "feature descriptions have at least lint.descminwords words", Severity: SeverityWarning, check: checkDescMinWords},
+	{Name: "param-io", Description: "io of the parameters is I, O or IO", Severity: SeverityError, check: checkParamIO},
+	{Name: "scenario-start", Description: "scenarios start with when or given", Severity: SeverityWarning, check: checkScenarioStart},
+	{Name: "api-drift", Description: "features with api match the operations of lint.openapi", Severity: SeverityWarning, check: checkApiDrift},
+	{Name: "no-placeholder", Description: "no lint.placeholders such as TBD in the text, e.g. enabled by the config of client builds", Severity: SeverityOff, check: checkPlaceholders},
+}
+
+// Rules returns all rules
+func Rules() []Rule {
+	result := []Rule{}
+	for _, rule := range rules {
+		result = append(result, *rule)
+	}
+	return result
+}
+
+// FindRule returns the rule by name, nil if not found
+func FindRule(name string) *Rule {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// featureId is the ID of the feature in the message
+func featureId(feature *docb.Feature) string {
+	if id := strings.Join(docb.ToStrArray(feature.Id), " "); id != "" {
+		return id
+	}
+	return strings.Join(docb.ToStrArray(feature.Name), " ")
+}
+
+// checkDuplicateIds reports the feature and screen IDs defined more than once across all inputs
+func checkDuplicateIds(c *context) {
+	features := map[string]docb.Position{}
+	screens := map[string]docb.Position{}
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		for _, id := range docb.ToStrArray(feature.Id) {
+			if pos, ok := features[id]; ok {
+				c.report(feature.Pos, "feature ID %s is already defined at %s:%d", id, pos.File, pos.Line)
+				continue
+			}
+			features[id] = feature.Pos
+		}
+		for _, screen := range feature.Screens {
+			if screen.Pos == (docb.Position{}) {
+				// inherited from the defaults or the extended feature
+				continue
+			}
+			for _, id := range docb.ToStrArray(screen.Id) {
+				if pos, ok := screens[id]; ok {
+					c.report(screen.Pos, "screen ID %s is already defined at %s:%d", id, pos.File, pos.Line)
+					continue
+				}
+				screens[id] = screen.Pos
+			}
+		}
+	})
+}
+
+// itemPos returns the position of the item of the feature, or of the feature if unknown, e.g. the inherited item
+func itemPos(pos docb.Position, feature *docb.Feature) docb.Position {
+	if pos == (docb.Position{}) {
+		return feature.Pos
+	}
+	return pos
+}
+
+// checkIdFormat reports the feature IDs not matching the pattern of the module, * is the pattern of all modules
+func checkIdFormat(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		re, ok := c.patterns[module.Name]
+		if !ok {
+			if re, ok = c.patterns["*"]; !ok {
+				return
+			}
+		}
+		ids := docb.ToStrArray(feature.Id)
+		if len(ids) == 0 {
+			c.report(feature.Pos, "feature %s in module %s has no ID", feature.Name, module.Name)
+		}
+		for _, id := range ids {
+			if !re.MatchString(id) {
+				c.report(feature.Pos, "feature ID %s does not match %s of module %s", id, re, module.Name)
+			}
+		}
+	})
+}
+
+// checkReferences reports the IDs mentioned in the free text that are not any feature or screen
+func checkReferences(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		reported := map[string]bool{}
+		texts(reflect.ValueOf(*feature), "", feature.Pos, func(field, text string, pos docb.Position) {
+			for _, m := range c.index.Mentions(text) {
+				if !c.index.Resolves(m.Id) && !reported[m.Id] {
+					c.report(pos, "%s mentioned in %s of feature %s is not a feature or screen ID", m.Id, field, featureId(feature))
+					reported[m.Id] = true
+				}
+			}
+		})
+	})
+}
+
+func checkScenarioRequired(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		if len(feature.Scenarios) == 0 {
+			c.report(feature.Pos, "feature %s has no scenario", featureId(feature))
+		}
+	})
+}
+
+func checkTestRequired(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		if len(feature.Tests) == 0 {
+			c.report(feature.Pos, "feature %s has no test", featureId(feature))
+		}
+	})
+}
+
+func checkDescMinWords(c *context) {
+	min := c.cfg.Lint.DescMinWords
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		words := len(strings.Fields(strings.Join(docb.ToStrArray(feature.Desc), " ")))
+		if words < min {
+			c.report(feature.Pos, "description of feature %s has %d words, at least %d words are expected", featureId(feature), words, min)
+		}
+	})
+}
+
+var paramIO = []string{"I", "O", "IO"}
+
+func checkParamIO(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		for _, param := range feature.Parameters {
+			for _, io := range docb.ToStrArray(param.IO) {
+				if io != "" && !funk.ContainsString(paramIO, io) {
+					c.report(itemPos(param.Pos, feature), "io %s of parameter %s in feature %s must be one of %s",
+						io, strings.Join(docb.ToStrArray(param.Field), " "), featureId(feature), strings.Join(paramIO, ", "))
+				}
+			}
+		}
+	})
+}
+
+var scenarioStarts = []string{"when", "given"}
+
+func checkScenarioStart(c *context) {
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		for _, scenario := range feature.Scenarios {
+			if len(scenario.Desc) == 0 {
+				continue
+			}
+			keyword, _ := docb.SplitGherkinWord(scenario.Desc[0])
+			if !funk.ContainsString(scenarioStarts, strings.ToLower(keyword)) {
+				c.report(itemPos(scenario.Pos, feature), "scenario %s of feature %s starts with %q instead of %s",
+					strings.Join(docb.ToStrArray(scenario.Name), " "), featureId(feature), scenario.Desc[0], strings.Join(scenarioStarts, " or "))
+			}
+		}
+	})
+}
+
+func checkPlaceholders(c *context) {
+	words := []string{}
+	for _, p := range c.cfg.Lint.Placeholders {
+		if strings.TrimSpace(p) != "" {
+			words = append(words, regexp.QuoteMeta(strings.TrimSpace(p)))
+		}
+	}
+	if len(words) == 0 {
+		return
+	}
+	re := regexp.MustCompile(`\b(?:` + strings.Join(words, "|") + `)\b`)
+	c.features(func(module *docb.Module, feature *docb.Feature) {
+		reported := map[string]bool{}
+		texts(reflect.ValueOf(*feature), "", feature.Pos, func(field, text string, pos docb.Position) {
+			if word := re.FindString(text); word != "" && !reported[field] {
+				c.report(pos, "placeholder %s in %s of feature %s", word, field, featureId(feature))
+				reported[field] = true
+			}
+		})
+	})
+}
+
+// fields which are not free text
+var notTextFields = []string{"Id", "Image", "Api", "Pos"}
+
+// texts calls the function with every string in the value, the yaml name of the top-level field and the
+// position of the nearest item having it, e.g. the test
+func texts(value reflect.Value, field string, pos docb.Position, fn func(field, text string, pos docb.Position)) {
+	switch value.Kind() {
+	case reflect.String:
+		fn(field, value.String(), pos)
+	case reflect.Struct:
+		if f := value.FieldByName("Pos"); f.IsValid() {
+			if p, ok := f.Interface().(docb.Position); ok && p != (docb.Position{}) {
+				pos = p
+			}
+		}
+		for i := 0; i < value.NumField(); i++ {
+			f := value.Type().Field(i)
+			if funk.ContainsString(notTextFields, f.Name) {
+				continue
+			}
+			name := field
+			if name == "" {
+				name = strings.Split(f.Tag.Get("yaml"), ",")[0]
+			}
+			texts(value.Field(i), name, pos, fn)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			texts(value.Index(i), field, pos, fn)
+		}
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			texts(value.MapIndex(key), field, pos, fn)
+		}
+	case reflect.Interface, reflect.Ptr:
+		if !value.IsNil() {
+			texts(value.Elem(), field, pos, fn)
+		}
+	}
+}
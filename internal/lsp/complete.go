@@ -0,0 +1,181 @@
+package lsp
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thoas/go-funk"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+var (
+	// indentation and the list marks before the key or value, e.g. "  - "
+	leadPattern = regexp.MustCompile(`^(\s*(?:-\s+)*)(.*)$`)
+	// the key of the line, e.g. "  - name: value"
+	keyPattern = regexp.MustCompile(`^(\s*(?:-\s+)*)([A-Za-z0-9_.+-]+):(?:\s+(.*))?$`)
+)
+
+// completion suggests the keys of the model, the Gherkin keywords in the scenarios and the known ids
+func (s *server) completion(doc *document, pos position) []completionItem {
+	line := doc.line(pos.Line)
+	prefix := line[:byteOffset(line, pos.Character)]
+	m := leadPattern.FindStringSubmatch(prefix)
+	lead, rest := m[1], m[2]
+	path := parentKeys(doc, pos.Line, len(lead))
+
+	if kv := keyPattern.FindStringSubmatch(strings.TrimSpace(rest)); kv != nil {
+		return s.valueItems(append(path, strings.TrimSuffix(kv[2], "+")))
+	}
+	if strings.Contains(rest, ":") {
+		return nil
+	}
+
+	if t := typeAt(path); t != nil && t.Kind() == reflect.Struct {
+		if strings.Contains(rest, " ") {
+			return nil
+		}
+		return keyItems(t)
+	}
+	if !strings.Contains(lead, "-") {
+		return nil
+	}
+	switch {
+	case hasSuffix(path, "scenarios", "desc"):
+		if !strings.Contains(rest, " ") {
+			return gherkinItems()
+		}
+		// the step refers to the screens and features
+		return s.symbolItems(screenSymbol, featureSymbol)
+	case hasSuffix(path, "others", "reference"):
+		return s.symbolItems(featureSymbol)
+	}
+	return nil
+}
+
+// valueItems suggests the value of the key
+func (s *server) valueItems(path []string) []completionItem {
+	switch {
+	case hasSuffix(path, "resources", "name"):
+		return s.symbolItems(resourceSymbol)
+	case hasSuffix(path, "screens", "id"):
+		return s.symbolItems(screenSymbol)
+	case hasSuffix(path, "extends"), hasSuffix(path, "others", "reference"):
+		return s.symbolItems(featureSymbol)
+	}
+	return nil
+}
+
+func (s *server) symbolItems(kinds ...string) []completionItem {
+	seen := map[string]bool{}
+	items := []completionItem{}
+	for _, sym := range s.symbols() {
+		if seen[sym.kind+sym.id] || !funk.ContainsString(kinds, sym.kind) {
+			continue
+		}
+		seen[sym.kind+sym.id] = true
+		kind := kindReference
+		if sym.kind == resourceSymbol {
+			kind = kindValue
+		}
+		items = append(items, completionItem{Label: sym.id, Kind: kind, Detail: strings.TrimSpace(sym.kind + " " + sym.name)})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+// keys resolved while loading, they are not in the model
+var loadingKeys = map[reflect.Type][]string{
+	reflect.TypeOf(docb.ProgSpec{}): {"defaults"},
+	reflect.TypeOf(docb.Module{}):   {"defaults"},
+	reflect.TypeOf(docb.Feature{}):  {"extends"},
+}
+
+func keyItems(t reflect.Type) []completionItem {
+	items := []completionItem{}
+	for i := 0; i < t.NumField(); i++ {
+		name := yamlName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		items = append(items, completionItem{Label: name, Kind: kindProperty, InsertText: name + ": "})
+	}
+	for _, name := range loadingKeys[t] {
+		items = append(items, completionItem{Label: name, Kind: kindProperty, InsertText: name + ": "})
+	}
+	return items
+}
+
+func gherkinItems() []completionItem {
+	items := []completionItem{}
+	for _, keyword := range docb.GherkinKeywords {
+		keyword = strings.ToUpper(keyword[:1]) + keyword[1:]
+		items = append(items, completionItem{Label: keyword, Kind: kindKeyword, InsertText: keyword + " "})
+	}
+	return items
+}
+
+// parentKeys returns the keys of the mappings containing the line, the key of the parent is
+// the nearest line above indented less than the column
+func parentKeys(doc *document, n, column int) []string {
+	keys := []string{}
+	for i := n - 1; i >= 0 && column > 0; i-- {
+		m := keyPattern.FindStringSubmatch(doc.line(i))
+		if m == nil || len(m[1]) >= column {
+			continue
+		}
+		keys = append([]string{strings.TrimSuffix(m[2], "+")}, keys...)
+		column = len(m[1])
+	}
+	return keys
+}
+
+// typeAt returns the type of the model at the key path, the lists are transparent
+func typeAt(path []string) reflect.Type {
+	t := elemType(reflect.TypeOf(docb.ProgSpec{}))
+	for _, key := range path {
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		if key == "defaults" && funk.ContainsString(loadingKeys[t], key) {
+			// defaults of the features
+			t = reflect.TypeOf(docb.Feature{})
+			continue
+		}
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			if yamlName(t.Field(i)) == key {
+				t = elemType(t.Field(i).Type)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return t
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+func yamlName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func hasSuffix(path []string, keys ...string) bool {
+	if len(path) < len(keys) {
+		return false
+	}
+	return reflect.DeepEqual(path[len(path)-len(keys):], keys)
+}
@@ -0,0 +1,106 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is the file opened in the editor, the text may be unsaved
+type document struct {
+	uri   string
+	path  string
+	text  string
+	lines []string
+}
+
+func newDocument(uri, text string) *document {
+	return &document{uri: uri, path: uriToPath(uri), text: text, lines: strings.Split(text, "\n")}
+}
+
+func (d *document) line(n int) string {
+	if n < 0 || n >= len(d.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(d.lines[n], "\r")
+}
+
+// lineRange returns the range of the whole line
+func (d *document) lineRange(n int) textRange {
+	return textRange{Start: position{Line: n}, End: position{Line: n, Character: utf16Len(d.line(n))}}
+}
+
+// word characters of the feature and screen ids
+var wordChars = regexp.MustCompile(`[A-Za-z0-9_.\-]`)
+
+// wordAt returns the word under the position and its range
+func (d *document) wordAt(pos position) (string, textRange) {
+	line := d.line(pos.Line)
+	offset := byteOffset(line, pos.Character)
+	start, end := offset, offset
+	for start > 0 && wordChars.MatchString(line[start-1:start]) {
+		start--
+	}
+	for end < len(line) && wordChars.MatchString(line[end:end+1]) {
+		end++
+	}
+	// a dot at the end is the punctuation of the sentence
+	word := strings.TrimRight(line[start:end], ".")
+	return word, textRange{
+		Start: position{Line: pos.Line, Character: utf16Len(line[:start])},
+		End:   position{Line: pos.Line, Character: utf16Len(line[:start+len(word)])},
+	}
+}
+
+// byteOffset converts the UTF-16 character offset of the position to byte offset
+func byteOffset(line string, char int) int {
+	n := 0
+	for i, r := range line {
+		if n >= char {
+			return i
+		}
+		n += utf16RuneLen(r)
+	}
+	return len(line)
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	path := u.Path
+	// file:///C:/dir on Windows
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return filepath.FromSlash(path)
+}
+
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,210 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zrs01/pst/internal/docb"
+	"gopkg.in/yaml.v3"
+)
+
+// kinds of the symbol
+const (
+	featureSymbol  = "feature"
+	screenSymbol   = "screen"
+	resourceSymbol = "resource"
+)
+
+// symbol is a feature, screen or resource defined in the spec files
+type symbol struct {
+	kind  string
+	id    string
+	name  string
+	desc  string
+	image string // absolute path of the screen image
+	loc   location
+}
+
+type indexedFile struct {
+	modTime time.Time
+	symbols []symbol
+}
+
+// index caches the symbols of the spec files in the workspace until the file is modified
+type index struct {
+	files map[string]*indexedFile
+}
+
+func newIndex() *index {
+	return &index{files: map[string]*indexedFile{}}
+}
+
+// symbols returns the symbols of the workspace files, the opened documents replace the saved files
+func (s *server) symbols() []symbol {
+	opened := map[string]bool{}
+	result := []symbol{}
+	for _, doc := range s.docs {
+		opened[doc.path] = true
+		result = append(result, scanSymbols(doc.uri, []byte(doc.text), s.imageDir(doc.path))...)
+	}
+	for _, root := range s.roots {
+		files, err := docb.ResolveInputFiles(root)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			path, _ := filepath.Abs(file)
+			if opened[path] {
+				continue
+			}
+			result = append(result, s.index.symbols(path, s.imageDir(path))...)
+		}
+	}
+	return result
+}
+
+func (x *index) symbols(path, imageDir string) []symbol {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if f, ok := x.files[path]; ok && f.modTime.Equal(info.ModTime()) {
+		return f.symbols
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	f := &indexedFile{modTime: info.ModTime(), symbols: scanSymbols(pathToURI(path), content, imageDir)}
+	x.files[path] = f
+	return f.symbols
+}
+
+// imageDir is the directory of the relative image path, same as the build
+func (s *server) imageDir(path string) string {
+	if s.args.Options.BaseDir != "" {
+		return s.args.Options.BaseDir
+	}
+	return filepath.Dir(path)
+}
+
+// scanSymbols finds the features, screens and resources in the file including the included fragments,
+// the file is not loaded by the builder since the symbols are needed even the file is incomplete
+func scanSymbols(uri string, content []byte, imageDir string) []symbol {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil
+	}
+	symbols := []symbol{}
+	var walk func(node *yaml.Node, key string)
+	walk = func(node *yaml.Node, key string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, item := range node.Content {
+				walk(item, key)
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				if item.Kind == yaml.MappingNode {
+					symbols = append(symbols, newSymbols(uri, key, item, imageDir)...)
+				}
+				walk(item, key)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1], strings.TrimSuffix(node.Content[i].Value, "+"))
+			}
+		}
+	}
+	walk(&root, "")
+	return symbols
+}
+
+// newSymbols returns the symbols of the item in the list of features, screens or resources
+func newSymbols(uri, key string, item *yaml.Node, imageDir string) []symbol {
+	kind, idKey := "", "id"
+	switch key {
+	case "features":
+		kind = featureSymbol
+	case "screens":
+		kind = screenSymbol
+	case "resources":
+		kind, idKey = resourceSymbol, "name"
+	default:
+		return nil
+	}
+
+	symbols := []symbol{}
+	for _, id := range scalars(mappingValue(item, idKey)) {
+		sym := symbol{
+			kind: kind,
+			id:   id.Value,
+			loc:  location{URI: uri, Range: nodeRange(id)},
+		}
+		if kind != resourceSymbol {
+			sym.name = strings.Join(values(mappingValue(item, "name")), " ")
+			sym.desc = strings.Join(values(mappingValue(item, "desc")), "\n")
+		}
+		if file := mappingValue(mappingValue(item, "image"), "file"); file != nil && file.Value != "" {
+			sym.image = file.Value
+			if !filepath.IsAbs(sym.image) {
+				sym.image = filepath.Join(imageDir, sym.image)
+			}
+			sym.image, _ = filepath.Abs(sym.image)
+		}
+		symbols = append(symbols, sym)
+	}
+	return symbols
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.TrimSuffix(node.Content[i].Value, "+") == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalars returns the value or the items of the list, same as docb.ToStrArray
+func scalars(node *yaml.Node) []*yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.ScalarNode {
+		if node.Value == "" {
+			return nil
+		}
+		return []*yaml.Node{node}
+	}
+	result := []*yaml.Node{}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			result = append(result, scalars(item)...)
+		}
+	}
+	return result
+}
+
+func values(node *yaml.Node) []string {
+	result := []string{}
+	for _, n := range scalars(node) {
+		result = append(result, n.Value)
+	}
+	return result
+}
+
+// nodeRange returns the range of the scalar, the line and column of yaml are 1-based
+func nodeRange(node *yaml.Node) textRange {
+	start := position{Line: node.Line - 1, Character: node.Column - 1}
+	end := start
+	if !strings.Contains(node.Value, "\n") {
+		end.Character += utf16Len(node.Value)
+	}
+	return textRange{Start: start, End: end}
+}
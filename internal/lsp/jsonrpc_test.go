@@ -0,0 +1,65 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func frame(body string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestServeSkipsMalformedMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		bad  string
+	}{
+		{"invalid json", frame(`{"jsonrpc":"2.0","id":1,"method":`)},
+		{"not an object", frame(`[1,2]`)},
+		{"invalid length", "Content-Length: abc\r\n\r\n"},
+		{"negative length", "Content-Length: -5\r\n\r\n"},
+		{"missing length", "Content-Type: application/json\r\n\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.bad + frame(`{"jsonrpc":"2.0","id":2,"method":"unknown/method"}`) + frame(`{"jsonrpc":"2.0","method":"exit"}`)
+			var out bytes.Buffer
+			if err := Serve(strings.NewReader(in), &out, Args{}); err != nil {
+				t.Fatalf("server stopped: %v", err)
+			}
+			codes := responseCodes(t, out.String())
+			if len(codes) != 2 || codes[0] != codeParseError || codes[1] != codeMethodNotFound {
+				t.Errorf("got error codes %v, want [%d %d]", codes, codeParseError, codeMethodNotFound)
+			}
+		})
+	}
+}
+
+func TestServeStopsAtEOF(t *testing.T) {
+	var out bytes.Buffer
+	if err := Serve(strings.NewReader(""), &out, Args{}); err != nil {
+		t.Fatal(err)
+	}
+	// truncated body is a broken stream
+	if err := Serve(strings.NewReader("Content-Length: 50\r\n\r\n{}"), &out, Args{}); err == nil {
+		t.Error("truncated message should stop the server")
+	}
+}
+
+// responseCodes returns the error codes of the framed responses
+func responseCodes(t *testing.T, out string) []int {
+	t.Helper()
+	codes := []int{}
+	for _, part := range strings.Split(out, "Content-Length: ")[1:] {
+		body := part[strings.Index(part, "\r\n\r\n")+4:]
+		resp := errorResponse{}
+		if err := json.Unmarshal([]byte(body), &resp); err != nil {
+			t.Fatalf("invalid response %s: %v", body, err)
+		}
+		codes = append(codes, resp.Error.Code)
+	}
+	return codes
+}
@@ -0,0 +1,259 @@
+// language server of the spec files for the editors
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+	"github.com/zrs01/pst/internal/config"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/lint"
+	"gopkg.in/yaml.v3"
+)
+
+// Args are the arguments of docb.Load to validate the spec
+type Args struct {
+	ConfigFile string
+	Options    docb.Options
+}
+
+type server struct {
+	args  Args
+	conn  *conn
+	roots []string             // workspace folders searched for the symbols
+	docs  map[string]*document // uri -> opened document
+	index *index
+}
+
+// Serve runs the language server on the reader and writer, usually stdin and stdout, until the editor exits
+func Serve(r io.Reader, w io.Writer, args Args) error {
+	s := &server{args: args, conn: newConn(r, w), docs: map[string]*document{}, index: newIndex()}
+	s.args.Options.Split = ""
+	for {
+		req, err := s.conn.read()
+		if err != nil {
+			if perr, ok := err.(*parseError); ok {
+				// skip the message, the id is unknown
+				logrus.Warn(perr.message)
+				if err := s.conn.replyError(nil, codeParseError, perr.message); err != nil {
+					return err
+				}
+				continue
+			}
+			if eris.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(req); err != nil {
+			logrus.Error(eris.ToString(err, false))
+		}
+	}
+}
+
+func (s *server) handle(req *request) error {
+	switch req.Method {
+	case "initialize":
+		params := initializeParams{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.conn.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		for _, folder := range params.WorkspaceFolders {
+			s.roots = append(s.roots, uriToPath(folder.URI))
+		}
+		if len(s.roots) == 0 && params.RootURI != "" {
+			s.roots = append(s.roots, uriToPath(params.RootURI))
+		}
+		return s.conn.reply(req.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   1, // full content on change
+				"completionProvider": map[string]interface{}{"triggerCharacters": []string{":", " ", "-"}},
+				"definitionProvider": true,
+				"hoverProvider":      true,
+			},
+			"serverInfo": map[string]string{"name": "pst"},
+		})
+	case "shutdown":
+		return s.conn.reply(req.ID, nil)
+	case "textDocument/didOpen":
+		params := didOpenParams{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return eris.Wrap(err, "invalid didOpen params")
+		}
+		s.docs[params.TextDocument.URI] = newDocument(params.TextDocument.URI, params.TextDocument.Text)
+		return s.publishDiagnostics()
+	case "textDocument/didChange":
+		params := didChangeParams{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return eris.Wrap(err, "invalid didChange params")
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.docs[params.TextDocument.URI] = newDocument(params.TextDocument.URI, text)
+		// the change may break or fix the documents including it
+		return s.publishDiagnostics()
+	case "textDocument/didClose":
+		params := didCloseParams{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return eris.Wrap(err, "invalid didClose params")
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/completion", "textDocument/definition", "textDocument/hover":
+		params := positionParams{}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.conn.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return s.conn.reply(req.ID, nil)
+		}
+		switch req.Method {
+		case "textDocument/completion":
+			return s.conn.reply(req.ID, s.completion(doc, params.Position))
+		case "textDocument/definition":
+			return s.conn.reply(req.ID, s.definition(doc, params.Position))
+		default:
+			return s.conn.reply(req.ID, s.hover(doc, params.Position))
+		}
+	}
+	if !req.isNotification() {
+		return s.conn.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method %s not supported", req.Method))
+	}
+	return nil
+}
+
+// definition returns the locations of the feature or screen with the id under the position
+func (s *server) definition(doc *document, pos position) []location {
+	word, _ := doc.wordAt(pos)
+	if word == "" {
+		return nil
+	}
+	locations := []location{}
+	for _, kind := range []string{featureSymbol, screenSymbol} {
+		for _, sym := range s.symbols() {
+			if sym.kind == kind && sym.id == word {
+				locations = append(locations, sym.loc)
+			}
+		}
+		if len(locations) > 0 {
+			break
+		}
+	}
+	return locations
+}
+
+// hover previews the screen or feature with the id under the position
+func (s *server) hover(doc *document, pos position) *hover {
+	word, rng := doc.wordAt(pos)
+	if word == "" {
+		return nil
+	}
+	for _, sym := range s.symbols() {
+		if sym.id != word || sym.kind == resourceSymbol {
+			continue
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "**%s** %s", sym.id, sym.name)
+		if sym.desc != "" {
+			fmt.Fprintf(&sb, "\n\n%s", strings.ReplaceAll(sym.desc, "\\n", "\n"))
+		}
+		if sym.image != "" {
+			fmt.Fprintf(&sb, "\n\n![%s](%s)", sym.id, pathToURI(sym.image))
+		}
+		return &hover{Contents: markupContent{Kind: "markdown", Value: sb.String()}, Range: &rng}
+	}
+	return nil
+}
+
+/* ------------------------------- DIAGNOSTICS ------------------------------ */
+
+// the line number in the error of yaml and the loader, e.g. "yaml: line 3: ..."
+var errorLine = regexp.MustCompile(`line (\d+)`)
+
+func (s *server) publishDiagnostics() error {
+	for _, doc := range s.docs {
+		params := publishDiagnosticsParams{URI: doc.uri, Diagnostics: s.diagnose(doc)}
+		if err := s.conn.notify("textDocument/publishDiagnostics", params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diagnose validates the document by loading it the same way as the build
+func (s *server) diagnose(doc *document) []diagnostic {
+	diagnostics := []diagnostic{}
+	if !strings.EqualFold(filepath.Ext(doc.path), ".toml") {
+		var root yaml.Node
+		if err := yaml.Unmarshal([]byte(doc.text), &root); err != nil {
+			return append(diagnostics, s.newDiagnostic(doc, err.Error()))
+		}
+		// the included fragment is validated with the spec including it
+		if len(root.Content) == 0 || mappingValue(root.Content[0], "modules") == nil {
+			return diagnostics
+		}
+	}
+
+	opts := s.args.Options
+	opts.Overlay = map[string][]byte{}
+	for _, d := range s.docs {
+		opts.Overlay[d.path] = []byte(d.text)
+	}
+	specs, err := docb.Load(s.args.ConfigFile, doc.path, opts)
+	if err != nil {
+		return append(diagnostics, s.newDiagnostic(doc, eris.ToString(err, false)))
+	}
+
+	// lint issues of the document, the included files are reported when they are opened
+	cfg, err := config.NewConfig(s.args.ConfigFile)
+	if err != nil {
+		return append(diagnostics, s.newDiagnostic(doc, eris.ToString(err, false)))
+	}
+	issues, err := lint.Check(specs, cfg, lint.Options{Overlay: opts.Overlay})
+	if err != nil {
+		return append(diagnostics, s.newDiagnostic(doc, eris.ToString(err, false)))
+	}
+	for _, issue := range issues {
+		if path, _ := filepath.Abs(issue.File); path != doc.path {
+			continue
+		}
+		d := diagnostic{Range: doc.lineRange(issue.Line - 1), Severity: lintSeverity(issue.Severity), Code: issue.Rule, Source: "pst", Message: issue.Message}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// newDiagnostic reports the error on the line mentioned by the message, the first line otherwise
+func (s *server) newDiagnostic(doc *document, message string) diagnostic {
+	line := 0
+	if m := errorLine.FindAllStringSubmatch(message, -1); len(m) > 0 {
+		if n, err := strconv.Atoi(m[len(m)-1][1]); err == nil && n > 0 {
+			line = n - 1
+		}
+	}
+	return diagnostic{Range: doc.lineRange(line), Severity: severityError, Source: "pst", Message: message}
+}
+
+// lintSeverity maps the severity of the lint issue to the diagnostic
+func lintSeverity(severity string) int {
+	switch severity {
+	case lint.SeverityError:
+		return severityError
+	case lint.SeverityInfo:
+		return severityInformation
+	}
+	return severityWarning
+}
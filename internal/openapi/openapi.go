@@ -0,0 +1,335 @@
+// features generated from the operations of the OpenAPI (or Swagger 2) document
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/zrs01/pst/internal/docb"
+	"gopkg.in/yaml.v3"
+)
+
+// methods of the path item in the order of the features
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Document is the OpenAPI document in YAML or JSON
+type Document struct {
+	File string
+	root *yaml.Node
+}
+
+// Operation is the operation of the path
+type Operation struct {
+	Method string // upper case
+	Path   string
+	node   *yaml.Node
+	item   *yaml.Node // path item, for the parameters shared by the operations
+}
+
+// Load reads the document
+func Load(file string) (*Document, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the OpenAPI document %s", file)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, eris.Wrapf(err, "failed to unmarshal the OpenAPI document %s", file)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, eris.Errorf("invalid OpenAPI document %s", file)
+	}
+	d := &Document{File: file, root: root.Content[0]}
+	if value(d.root, "openapi") == nil && value(d.root, "swagger") == nil {
+		return nil, eris.Errorf("%s is not an OpenAPI document, openapi or swagger version is missing", file)
+	}
+	return d, nil
+}
+
+// Title returns the title of the API
+func (d *Document) Title() string {
+	return text(value(value(d.root, "info"), "title"))
+}
+
+// Operations returns the operations in the order of the paths
+func (d *Document) Operations() []Operation {
+	ops := []Operation{}
+	paths := value(d.root, "paths")
+	if paths == nil {
+		return ops
+	}
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i].Value, d.resolve(paths.Content[i+1])
+		for _, method := range methods {
+			if node := value(item, method); node != nil {
+				ops = append(ops, Operation{Method: strings.ToUpper(method), Path: path, node: node, item: item})
+			}
+		}
+	}
+	return ops
+}
+
+// Find returns the operation by method and path, nil if not found
+func (d *Document) Find(method, path string) *Operation {
+	for _, op := range d.Operations() {
+		if strings.EqualFold(op.Method, method) && op.Path == path {
+			return &op
+		}
+	}
+	return nil
+}
+
+var nonIdChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Feature converts the operation to the feature
+func (d *Document) Feature(op Operation) docb.Feature {
+	id := text(value(op.node, "operationId"))
+	if id == "" {
+		id = strings.Trim(nonIdChars.ReplaceAllString(op.Method+"_"+op.Path, "_"), "_")
+	}
+	name := text(value(op.node, "summary"))
+	if name == "" {
+		name = id
+	}
+	desc := text(value(op.node, "description"))
+	if desc == "" {
+		desc = text(value(op.node, "summary"))
+	}
+	mode := "Online Update"
+	if op.Method == "GET" || op.Method == "HEAD" {
+		mode = "Online Enquiry"
+	}
+
+	feature := docb.Feature{Id: id, Name: name, Mode: mode, Api: &docb.Api{Method: op.Method, Path: op.Path}}
+	if desc != "" {
+		feature.Desc = desc
+	}
+	feature.Parameters = d.parameters(op)
+	feature.Tests = d.tests(op)
+	return feature
+}
+
+// parameters returns the request parameters and body as input, the fields of the success response as output
+func (d *Document) parameters(op Operation) []docb.Parameter {
+	params := []docb.Parameter{}
+
+	// parameters of the operation override the ones of the path item by name and location
+	defined := []*yaml.Node{}
+	for _, node := range []*yaml.Node{op.item, op.node} {
+		for _, p := range d.items(value(node, "parameters")) {
+			p = d.resolve(p)
+			replaced := false
+			for i, existing := range defined {
+				if text(value(existing, "name")) == text(value(p, "name")) && text(value(existing, "in")) == text(value(p, "in")) {
+					defined[i], replaced = p, true
+				}
+			}
+			if !replaced {
+				defined = append(defined, p)
+			}
+		}
+	}
+	for _, p := range defined {
+		in := text(value(p, "in"))
+		if in == "body" {
+			// swagger 2
+			d.fields(value(p, "schema"), "", 0, func(field, typ, desc string, required bool) {
+				params = append(params, parameter(field, "body: "+typ, "I", desc, required))
+			})
+			continue
+		}
+		schema := value(p, "schema")
+		if schema == nil {
+			schema = p // swagger 2 defines the type in the parameter
+		}
+		params = append(params, parameter(text(value(p, "name")), in+": "+d.typeOf(schema), "I", text(value(p, "description")), text(value(p, "required")) == "true"))
+	}
+
+	if body := d.resolve(value(op.node, "requestBody")); body != nil {
+		d.fields(d.contentSchema(body), "", 0, func(field, typ, desc string, required bool) {
+			params = append(params, parameter(field, "body: "+typ, "I", desc, required))
+		})
+	}
+
+	if response := d.successResponse(op); response != nil {
+		schema := value(response, "schema") // swagger 2
+		if schema == nil {
+			schema = d.contentSchema(response)
+		}
+		d.fields(schema, "", 0, func(field, typ, desc string, required bool) {
+			params = append(params, parameter(field, "response: "+typ, "O", desc, false))
+		})
+	}
+	return params
+}
+
+func parameter(field, data, io, desc string, required bool) docb.Parameter {
+	p := docb.Parameter{Field: field, Data: data, IO: io}
+	remarks := []string{}
+	if required {
+		remarks = append(remarks, "Required")
+	}
+	if desc != "" {
+		remarks = append(remarks, desc)
+	}
+	if len(remarks) > 0 {
+		p.Remarks = strings.Join(remarks, ". ")
+	}
+	return p
+}
+
+// tests returns one test per response as the expected result
+func (d *Document) tests(op Operation) []docb.Test {
+	tests := []docb.Test{}
+	responses := value(op.node, "responses")
+	if responses == nil {
+		return tests
+	}
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		code := responses.Content[i].Value
+		response := d.resolve(responses.Content[i+1])
+		expect := strings.TrimSpace(code + " " + text(value(response, "description")))
+		tests = append(tests, docb.Test{Desc: fmt.Sprintf("%s %s responding %s", op.Method, op.Path, code), Expect: expect})
+	}
+	return tests
+}
+
+// successResponse returns the first 2xx response, the default response otherwise
+func (d *Document) successResponse(op Operation) *yaml.Node {
+	responses := value(op.node, "responses")
+	if responses == nil {
+		return nil
+	}
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		if strings.HasPrefix(responses.Content[i].Value, "2") {
+			return d.resolve(responses.Content[i+1])
+		}
+	}
+	return d.resolve(value(responses, "default"))
+}
+
+// contentSchema returns the schema of the JSON content, the first content otherwise
+func (d *Document) contentSchema(node *yaml.Node) *yaml.Node {
+	content := value(node, "content")
+	if content == nil || len(content.Content) < 2 {
+		return nil
+	}
+	for i := 0; i+1 < len(content.Content); i += 2 {
+		if strings.Contains(content.Content[i].Value, "json") {
+			return value(content.Content[i+1], "schema")
+		}
+	}
+	return value(content.Content[1], "schema")
+}
+
+// maximum depth of the nested objects in the field names, e.g. address.city
+const maxDepth = 3
+
+// fields calls the function with the properties of the schema, the nested properties are named by the path
+func (d *Document) fields(schema *yaml.Node, prefix string, depth int, fn func(field, typ, desc string, required bool)) {
+	schema = d.resolve(schema)
+	if schema == nil || depth > maxDepth {
+		return
+	}
+	for _, sub := range d.items(value(schema, "allOf")) {
+		d.fields(sub, prefix, depth, fn)
+	}
+	if text(value(schema, "type")) == "array" {
+		// the items of the top-level array are named as the object, e.g. name instead of [].name
+		if prefix != "" {
+			prefix += "[]"
+		}
+		d.fields(value(schema, "items"), prefix, depth, fn)
+		return
+	}
+	properties := value(schema, "properties")
+	if properties == nil {
+		if prefix != "" && value(schema, "allOf") == nil {
+			fn(prefix, d.typeOf(schema), text(value(schema, "description")), false)
+		}
+		return
+	}
+	required := map[string]bool{}
+	for _, name := range d.items(value(schema, "required")) {
+		required[name.Value] = true
+	}
+	for i := 0; i+1 < len(properties.Content); i += 2 {
+		name := properties.Content[i].Value
+		property := d.resolve(properties.Content[i+1])
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if value(property, "properties") != nil || (text(value(property, "type")) == "array" && value(d.resolve(value(property, "items")), "properties") != nil) {
+			d.fields(property, name, depth+1, fn)
+			continue
+		}
+		fn(name, d.typeOf(property), text(value(property, "description")), required[properties.Content[i].Value])
+	}
+}
+
+// typeOf returns the type of the schema, e.g. string, string (date-time), array of integer
+func (d *Document) typeOf(schema *yaml.Node) string {
+	if ref := text(value(schema, "$ref")); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	typ := text(value(schema, "type"))
+	if typ == "array" {
+		return "array of " + d.typeOf(value(schema, "items"))
+	}
+	if format := text(value(schema, "format")); format != "" {
+		typ += " (" + format + ")"
+	}
+	if typ == "" {
+		typ = "object"
+	}
+	return typ
+}
+
+// resolve follows the local $ref, e.g. #/components/schemas/User
+func (d *Document) resolve(node *yaml.Node) *yaml.Node {
+	for i := 0; node != nil && i < 10; i++ {
+		ref := text(value(node, "$ref"))
+		if !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+		target := d.root
+		for _, key := range strings.Split(ref[2:], "/") {
+			key = strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+			if target = value(target, key); target == nil {
+				return nil
+			}
+		}
+		node = target
+	}
+	return node
+}
+
+func (d *Document) items(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// value returns the value of the key in the mapping, nil if not found
+func value(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func text(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return strings.TrimSpace(node.Value)
+}
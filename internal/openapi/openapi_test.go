@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zrs01/pst/internal/docb"
+)
+
+const openapi3 = `openapi: 3.0.0
+info: {title: User API}
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer, format: int64}}
+      - {name: lang, in: query, description: language, schema: {type: string}}
+    get:
+      operationId: getUser
+      summary: Get user
+      parameters:
+        - {name: lang, in: query, description: overridden, schema: {type: string}}
+      responses:
+        "200":
+          description: the user
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+        "404": {$ref: "#/components/responses/NotFound"}
+    put:
+      description: Update the user
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string, description: full name}
+                tags: {type: array, items: {type: string}}
+      responses:
+        default: {description: done}
+components:
+  responses:
+    NotFound: {description: not found}
+  schemas:
+    User:
+      type: object
+      properties:
+        name: {type: string}
+        address:
+          type: object
+          properties:
+            city: {type: string}
+        created: {type: string, format: date-time}
+`
+
+const swagger2 = `swagger: "2.0"
+paths:
+  /orders:
+    post:
+      parameters:
+        - name: order
+          in: body
+          schema:
+            type: object
+            properties:
+              qty: {type: integer}
+        - {name: dry, in: query, type: boolean}
+      responses:
+        "201":
+          description: created
+          schema: {type: array, items: {type: object, properties: {id: {type: string}}}}
+`
+
+// load writes the document into a temporary directory and loads it
+func load(t *testing.T, content string) *Document {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "openapi.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestFeature(t *testing.T) {
+	pathParams := []docb.Parameter{
+		{Field: "id", Data: "path: integer (int64)", IO: "I", Remarks: "Required"},
+	}
+	tests := []struct {
+		name    string
+		content string
+		want    []docb.Feature
+	}{
+		{
+			name:    "openapi 3",
+			content: openapi3,
+			want: []docb.Feature{
+				{
+					Id: "getUser", Name: "Get user", Desc: "Get user", Mode: "Online Enquiry", Api: &docb.Api{Method: "GET", Path: "/users/{id}"},
+					Parameters: append(append([]docb.Parameter{}, pathParams...),
+						docb.Parameter{Field: "lang", Data: "query: string", IO: "I", Remarks: "overridden"},
+						docb.Parameter{Field: "name", Data: "response: string", IO: "O"},
+						docb.Parameter{Field: "address.city", Data: "response: string", IO: "O"},
+						docb.Parameter{Field: "created", Data: "response: string (date-time)", IO: "O"},
+					),
+					Tests: []docb.Test{
+						{Desc: "GET /users/{id} responding 200", Expect: "200 the user"},
+						{Desc: "GET /users/{id} responding 404", Expect: "404 not found"},
+					},
+				},
+				{
+					Id: "PUT_users_id", Name: "PUT_users_id", Desc: "Update the user", Mode: "Online Update", Api: &docb.Api{Method: "PUT", Path: "/users/{id}"},
+					Parameters: append(append([]docb.Parameter{}, pathParams...),
+						docb.Parameter{Field: "lang", Data: "query: string", IO: "I", Remarks: "language"},
+						docb.Parameter{Field: "name", Data: "body: string", IO: "I", Remarks: "Required. full name"},
+						docb.Parameter{Field: "tags", Data: "body: array of string", IO: "I"},
+					),
+					Tests: []docb.Test{{Desc: "PUT /users/{id} responding default", Expect: "default done"}},
+				},
+			},
+		},
+		{
+			name:    "swagger 2",
+			content: swagger2,
+			want: []docb.Feature{
+				{
+					Id: "POST_orders", Name: "POST_orders", Mode: "Online Update", Api: &docb.Api{Method: "POST", Path: "/orders"},
+					Parameters: []docb.Parameter{
+						{Field: "qty", Data: "body: integer", IO: "I"},
+						{Field: "dry", Data: "query: boolean", IO: "I"},
+						{Field: "id", Data: "response: string", IO: "O"},
+					},
+					Tests: []docb.Test{{Desc: "POST /orders responding 201", Expect: "201 created"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := load(t, tt.content)
+			got := []docb.Feature{}
+			for _, op := range doc.Operations() {
+				got = append(got, doc.Feature(op))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d features, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("feature %d:\ngot  %+v\nwant %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	doc := load(t, openapi3)
+	if doc.Title() != "User API" {
+		t.Errorf("title %s", doc.Title())
+	}
+	if op := doc.Find("get", "/users/{id}"); op == nil || op.Method != "GET" {
+		t.Errorf("GET /users/{id} is not found")
+	}
+	if op := doc.Find("DELETE", "/users/{id}"); op != nil {
+		t.Errorf("DELETE /users/{id} should not be found")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "other.yaml")
+	if err := os.WriteFile(file, []byte("info: {title: x}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{
+		file:                            "is not an OpenAPI document",
+		filepath.Join(dir, "none.yaml"): "failed to read",
+	} {
+		if _, err := Load(file); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%s) = %v, want %s", file, err, want)
+		}
+	}
+}
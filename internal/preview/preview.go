@@ -0,0 +1,266 @@
+// live preview of the spec in browser
+package preview
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/watch"
+)
+
+//go:embed preview.html
+var pageTemplate string
+
+// Args are the arguments of docb.Build
+type Args struct {
+	ConfigFile   string
+	InputFile    string
+	DocumentFile string
+	Options      docb.Options
+}
+
+type server struct {
+	args    Args
+	page    *template.Template
+	clients map[chan struct{}]bool
+	mu      sync.Mutex
+}
+
+// Serve renders the spec as HTML on the address, the browser reloads when the files change
+func Serve(addr string, args Args) error {
+	s := &server{args: args, clients: map[chan struct{}]bool{}}
+	s.args.Options.Split = ""
+
+	page, err := template.New("preview").Funcs(template.FuncMap{
+		"lines":       lines,
+		"code":        code,
+		"blank":       isBlank,
+		"blankOthers": isBlankOthers,
+		"gherkin":     gherkin,
+		"image":       imageURL,
+		"inc":         func(i int) int { return i + 1 },
+	}).Parse(pageTemplate)
+	if err != nil {
+		return eris.Wrap(err, "failed to parse the page template")
+	}
+	s.page = page
+
+	// reuse the watch mode to notify the browsers
+	go func() {
+		err := watch.Run(func() ([]watch.Unit, error) {
+			return []watch.Unit{{
+				Name:  "preview",
+				Build: func() error { s.publish(); return nil },
+				Deps: func() ([]string, error) {
+					return docb.Dependencies(s.args.ConfigFile, s.args.InputFile, s.args.DocumentFile, s.args.Options)
+				},
+			}}, nil
+		}, watch.DefaultDebounce)
+		if err != nil {
+			logrus.Error(eris.ToString(err, false))
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handlePage)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/image", s.handleImage)
+	mux.HandleFunc("/download", s.handleDownload)
+
+	logrus.Infof("preview on http://%s", addr)
+	return eris.Wrap(http.ListenAndServe(addr, mux), "failed to start the server")
+}
+
+func (s *server) load() ([]docb.Spec, error) {
+	return docb.Load(s.args.ConfigFile, s.args.InputFile, s.args.Options)
+}
+
+func (s *server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	data := struct {
+		Title string
+		Specs []docb.Spec
+		Error string
+	}{Title: s.args.InputFile}
+
+	specs, err := s.load()
+	if err != nil {
+		// show the error and keep reloading so that the writer can fix it
+		data.Error = eris.ToString(err, false)
+	}
+	data.Specs = specs
+
+	var buf bytes.Buffer
+	if err := s.page.Execute(&buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+}
+
+// handleEvents sends the reload event by server-sent events
+func (s *server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+	ping := time.NewTicker(30 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: \n\n")
+			flusher.Flush()
+		case <-ping.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// handleImage serves the images of the screens only
+func (s *server) handleImage(w http.ResponseWriter, r *http.Request) {
+	file := r.URL.Query().Get("file")
+	specs, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, spec := range specs {
+		for _, module := range spec.Data.Modules {
+			for _, feature := range module.Features {
+				for _, screen := range feature.Screens {
+					if screen.Image.File == file {
+						http.ServeFile(w, r, file)
+						return
+					}
+				}
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// handleDownload builds the .docx by the normal pipeline
+func (s *server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	tmp, err := os.CreateTemp("", "pst-*.docx")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := docb.Build(s.args.ConfigFile, s.args.InputFile, tmp.Name(), s.args.DocumentFile, s.args.Options); err != nil {
+		http.Error(w, eris.ToString(err, false), http.StatusInternalServerError)
+		return
+	}
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	first := filepath.Base(strings.Split(s.args.InputFile, ",")[0])
+	name := strings.TrimSuffix(first, filepath.Ext(first)) + ".docx"
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	_, _ = io.Copy(w, f)
+}
+
+func (s *server) subscribe() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	s.clients[ch] = true
+	return ch
+}
+
+func (s *server) unsubscribe(ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, ch)
+}
+
+func (s *server) publish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+/* -------------------------------- TEMPLATE -------------------------------- */
+
+// lines returns the paragraphs of the value, each paragraph is split by the \n escape as the document
+func lines(v interface{}) [][]string {
+	if code, ok := docb.CodeOf(v, false); ok {
+		return [][]string{strings.Split(strings.TrimRight(code.Text, "\n"), "\n")}
+	}
+	result := [][]string{}
+	for _, text := range docb.ToStrArray(v) {
+		paragraph := []string{}
+		for _, line := range strings.Split(text, "\\n") {
+			paragraph = append(paragraph, strings.ReplaceAll(line, "\\t", "\t"))
+		}
+		result = append(result, paragraph)
+	}
+	return result
+}
+
+// code returns the text of the code block, see docb.CodeOf
+func code(v interface{}) string {
+	if code, ok := docb.CodeOf(v, true); ok {
+		return code.Text
+	}
+	return ""
+}
+
+func isBlank(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+func isBlankOthers(o docb.Others) bool {
+	return o.Reference == nil && o.Limits == nil && o.Program == nil && o.Remarks == nil
+}
+
+func gherkin(s string) struct{ Keyword, Text string } {
+	keyword, text := docb.SplitGherkinWord(s)
+	return struct{ Keyword, Text string }{keyword, text}
+}
+
+func imageURL(file string) template.URL {
+	return template.URL("image?file=" + url.QueryEscape(file))
+}
@@ -0,0 +1,258 @@
+// project manifest describing the whole documentation build
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+	"github.com/sirupsen/logrus"
+	"github.com/thoas/go-funk"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/internal/watch"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the manifest file name searched in the current directory
+const DefaultFile = "pst.yml"
+
+// Manifest holds the settings shared by the targets, the paths are relative to the manifest file
+type Manifest struct {
+	Config     string            `yaml:"config,omitempty"`
+	Template   string            `yaml:"template,omitempty"`
+	Format     string            `yaml:"format,omitempty"`
+	BaseDir    string            `yaml:"basedir,omitempty"`
+	Inputs     []string          `yaml:"inputs,omitempty"`
+	Appendices []string          `yaml:"appendices,omitempty"`
+	Vars       map[string]string `yaml:"vars,omitempty"`
+	Targets    []Target          `yaml:"targets,omitempty"`
+	dir        string
+}
+
+// Target is one output of the build, blank settings are taken from the manifest
+type Target struct {
+	Name       string            `yaml:"name,omitempty"`
+	Output     string            `yaml:"output,omitempty"`
+	Config     string            `yaml:"config,omitempty"`
+	Template   string            `yaml:"template,omitempty"`
+	Inputs     []string          `yaml:"inputs,omitempty"`
+	Appendices []string          `yaml:"appendices,omitempty"`
+	Vars       map[string]string `yaml:"vars,omitempty"` // merged with the manifest variables
+	Split      string            `yaml:"split,omitempty"`
+	Pattern    string            `yaml:"pattern,omitempty"`
+	Summary    bool              `yaml:"summary,omitempty"` // insert the statistics page at the front
+}
+
+// Load reads the manifest file
+func Load(file string) (*Manifest, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the manifest %s", file)
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(content, &m); err != nil {
+		return nil, eris.Wrapf(err, "failed to unmarshal the manifest %s", file)
+	}
+	m.dir = filepath.Dir(file)
+
+	names := map[string]bool{}
+	for i, t := range m.Targets {
+		if xstrings.IsBlank(t.Name) {
+			return nil, eris.Errorf("name of target #%d is missing in %s", i+1, file)
+		}
+		if names[t.Name] {
+			return nil, eris.Errorf("duplicated target %s in %s", t.Name, file)
+		}
+		if xstrings.IsBlank(t.Output) {
+			return nil, eris.Errorf("output of target %s is missing in %s", t.Name, file)
+		}
+		names[t.Name] = true
+	}
+	return &m, nil
+}
+
+// Build builds the targets by name, all targets if no name is provided
+func (m *Manifest) Build(vars map[string]string, names ...string) error {
+	targets, err := m.Select(names...)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		logrus.Infof("building target %s", t.Name)
+		if err := m.build(t, vars); err != nil {
+			return eris.Wrapf(err, "failed to build target %s", t.Name)
+		}
+	}
+	return nil
+}
+
+// Select returns the targets by name, all targets if no name is provided
+func (m *Manifest) Select(names ...string) ([]Target, error) {
+	if len(names) == 0 {
+		return m.Targets, nil
+	}
+	targets := []Target{}
+	for _, name := range names {
+		found := false
+		for _, t := range m.Targets {
+			if t.Name == name {
+				targets = append(targets, t)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, eris.Errorf("no such target %s, available targets: %s", name, strings.Join(m.TargetNames(), ", "))
+		}
+	}
+	return targets, nil
+}
+
+// TargetNames returns the names of all targets
+func (m *Manifest) TargetNames() []string {
+	names := []string{}
+	for _, t := range m.Targets {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// ConfigFile returns the config file of the manifest
+func (m *Manifest) ConfigFile() string {
+	return m.path(m.Config)
+}
+
+// InputFiles returns the comma separated inputs of the manifest and all targets
+func (m *Manifest) InputFiles() string {
+	inputs := append([]string{}, m.Inputs...)
+	for _, t := range m.Targets {
+		for _, input := range t.Inputs {
+			if !funk.ContainsString(inputs, input) {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+	return m.paths(inputs)
+}
+
+// SpecArgs returns the config file, inputs and options of the target to load the specs
+func (m *Manifest) SpecArgs(name string, vars map[string]string) (string, string, docb.Options, error) {
+	targets, err := m.Select(name)
+	if err != nil {
+		return "", "", docb.Options{}, err
+	}
+	args, err := m.args(targets[0], vars)
+	if err != nil {
+		return "", "", docb.Options{}, eris.Wrapf(err, "failed to resolve target %s", name)
+	}
+	return args.cfile, args.ifile, args.options, nil
+}
+
+// buildArgs are the arguments of docb.Build
+type buildArgs struct {
+	cfile, ifile, ofile, tfile string
+	options                    docb.Options
+}
+
+func (m *Manifest) build(t Target, vars map[string]string) error {
+	args, err := m.args(t, vars)
+	if err != nil {
+		return err
+	}
+	if args.options.Split == "" {
+		if err := os.MkdirAll(filepath.Dir(args.ofile), os.ModePerm); err != nil {
+			return eris.Wrapf(err, "failed to create the output directory of %s", args.ofile)
+		}
+	}
+	return docb.Build(args.cfile, args.ifile, args.ofile, args.tfile, args.options)
+}
+
+// Units returns the units for the watch mode, see Build for the arguments
+func (m *Manifest) Units(vars map[string]string, names ...string) ([]watch.Unit, error) {
+	targets, err := m.Select(names...)
+	if err != nil {
+		return nil, err
+	}
+	units := []watch.Unit{}
+	for _, t := range targets {
+		args, err := m.args(t, vars)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to resolve target %s", t.Name)
+		}
+		if args.options.Split == "" {
+			if err := os.MkdirAll(filepath.Dir(args.ofile), os.ModePerm); err != nil {
+				return nil, eris.Wrapf(err, "failed to create the output directory of %s", args.ofile)
+			}
+		}
+		us, err := watch.SpecUnits(t.Name, args.cfile, args.ifile, args.ofile, args.tfile, args.options)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to resolve target %s", t.Name)
+		}
+		units = append(units, us...)
+	}
+	return units, nil
+}
+
+func (m *Manifest) args(t Target, vars map[string]string) (*buildArgs, error) {
+	inputs := t.Inputs
+	if len(inputs) == 0 {
+		inputs = m.Inputs
+	}
+	if len(inputs) == 0 {
+		return nil, eris.New("no input is defined")
+	}
+	appendices := t.Appendices
+	if len(appendices) == 0 {
+		appendices = m.Appendices
+	}
+
+	// command line variables override the target, target overrides the manifest
+	merged := map[string]string{}
+	for _, vs := range []map[string]string{m.Vars, t.Vars, vars} {
+		for k, v := range vs {
+			merged[k] = v
+		}
+	}
+
+	return &buildArgs{
+		cfile: m.path(m.either(t.Config, m.Config)),
+		ifile: m.paths(inputs),
+		ofile: m.path(t.Output),
+		tfile: m.path(m.either(t.Template, m.Template)),
+		options: docb.Options{
+			Vars:     merged,
+			Format:   m.Format,
+			BaseDir:  m.path(m.BaseDir),
+			Split:    t.Split,
+			Pattern:  t.Pattern,
+			Appendix: m.paths(appendices),
+			Summary:  t.Summary,
+		},
+	}, nil
+}
+
+func (m *Manifest) either(value, fallback string) string {
+	if xstrings.IsNotBlank(value) {
+		return value
+	}
+	return fallback
+}
+
+// path makes the path relative to the manifest file
+func (m *Manifest) path(p string) string {
+	if xstrings.IsBlank(p) || p == docb.StdStream || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(m.dir, p)
+}
+
+// paths returns the comma separated paths accepted by the builder
+func (m *Manifest) paths(ps []string) string {
+	result := []string{}
+	for _, p := range ps {
+		result = append(result, m.path(p))
+	}
+	return strings.Join(result, ",")
+}
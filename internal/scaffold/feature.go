@@ -0,0 +1,322 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/shomali11/util/xstrings"
+	"github.com/sirupsen/logrus"
+	"github.com/zrs01/pst/internal/docb"
+	"gopkg.in/yaml.v3"
+)
+
+// FeatureArgs are the arguments of the new feature
+type FeatureArgs struct {
+	Module string // name of the module, as written in the spec
+	Id     string // generated from the existing IDs of the module if blank
+	Name   string
+}
+
+// prefix, number and suffix of the ID, e.g. UF + 010 + A
+var idPattern = regexp.MustCompile(`^(.*?)(\d+)(\D*)$`)
+
+// specFile is the spec file parsed as text so that the comments and formatting are kept on writing
+type specFile struct {
+	file  string
+	lines []string
+	root  *yaml.Node
+}
+
+// NewFeature appends the feature stub to the module found in the input files, it returns the file written and the ID
+func NewFeature(ifile string, args FeatureArgs) (string, string, error) {
+	files, err := docb.ResolveInputFiles(ifile)
+	if err != nil {
+		return "", "", err
+	}
+
+	var target *specFile
+	var module *yaml.Node
+	ids := map[string]bool{} // all feature IDs to keep the new one unique
+	for _, file := range files {
+		if file == docb.StdStream {
+			continue
+		}
+		spec, err := readSpecFile(file)
+		if err != nil {
+			return "", "", err
+		}
+		for _, id := range featureIds(spec.root) {
+			ids[id] = true
+		}
+		for _, m := range spec.modules() {
+			if strings.EqualFold(strings.TrimSpace(mappingString(m, "name")), strings.TrimSpace(args.Module)) {
+				if target != nil {
+					return "", "", eris.Errorf("module %s is found in both %s and %s", args.Module, target.file, file)
+				}
+				target, module = spec, m
+			}
+		}
+	}
+	if target == nil {
+		return "", "", eris.Errorf("module %s is not found in %s", args.Module, ifile)
+	}
+
+	// the features may be included from another file
+	features := mappingValue(module, "features")
+	if features != nil && features.Kind == yaml.ScalarNode && features.Tag == "!include" {
+		included := features.Value
+		if !filepath.IsAbs(included) {
+			included = filepath.Join(filepath.Dir(target.file), included)
+		}
+		if target, err = readSpecFile(included); err != nil {
+			return "", "", err
+		}
+		for _, id := range featureIds(target.root) {
+			ids[id] = true
+		}
+		if features = target.root.Content[0]; features.Kind != yaml.SequenceNode {
+			return "", "", eris.Errorf("the included features file %s should be a list", target.file)
+		}
+	}
+
+	id := args.Id
+	if xstrings.IsBlank(id) {
+		if id, err = nextId(itemIds(features), ids); err != nil {
+			return "", "", eris.Wrapf(err, "failed to generate the ID of module %s", args.Module)
+		}
+	} else if ids[id] {
+		return "", "", eris.Errorf("feature %s already exists", id)
+	}
+	name := args.Name
+	if xstrings.IsBlank(name) {
+		name = "TODO"
+	}
+	stub := strings.Split(strings.TrimRight(strings.NewReplacer("{id}", id, "{name}", name).Replace(featureStub), "\n"), "\n")
+
+	if features == target.root.Content[0] {
+		target.appendItem(stub)
+	} else if err := target.insertFeature(module, features, stub); err != nil {
+		return "", "", err
+	}
+	if err := target.write(); err != nil {
+		return "", "", err
+	}
+	logrus.Infof("added feature %s to module %s in %s", id, args.Module, target.file)
+	return target.file, id, nil
+}
+
+func readSpecFile(file string) (*specFile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read the file %s", file)
+	}
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(content, root); err != nil {
+		return nil, eris.Wrapf(err, "failed to unmarshal the file %s", file)
+	}
+	if len(root.Content) == 0 {
+		root.Content = []*yaml.Node{{Kind: yaml.SequenceNode}}
+	}
+	return &specFile{file: file, lines: strings.Split(string(content), "\n"), root: root}, nil
+}
+
+// modules returns the modules of the spec, or the items of the included list of modules
+func (s *specFile) modules() []*yaml.Node {
+	list := s.root.Content[0]
+	if list.Kind == yaml.MappingNode {
+		list = mappingValue(list, "modules")
+	}
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return list.Content
+}
+
+// insertFeature inserts the stub after the last feature of the module
+func (s *specFile) insertFeature(module, features *yaml.Node, stub []string) error {
+	if features != nil && features.Style&yaml.FlowStyle != 0 {
+		return eris.Errorf("features in flow style is not supported, line %d of %s", features.Line, s.file)
+	}
+	if features != nil && features.Kind != yaml.SequenceNode && (features.Tag != "!!null" || features.Value != "") {
+		return eris.Errorf("features should be a list, line %d of %s", features.Line, s.file)
+	}
+
+	key := mappingKey(module, "features")
+	if key == nil {
+		// no features yet, add the key at the end of the module
+		last := module.Content[len(module.Content)-2]
+		end := s.blockEnd(last.Line-1, last.Column-1)
+		indent := strings.Repeat(" ", module.Content[0].Column-1)
+		s.insert(end, append([]string{indent + "features:"}, indentLines(stub, indent+"  ")...))
+		return nil
+	}
+
+	indent := strings.Repeat(" ", key.Column+1)
+	if features.Kind == yaml.SequenceNode && len(features.Content) > 0 {
+		// align with the dash of the existing items
+		item := features.Content[0]
+		line := s.lines[item.Line-1]
+		if i := strings.LastIndex(line[:item.Column-1], "-"); i >= 0 {
+			indent = line[:i]
+		}
+	}
+	s.insert(s.blockEnd(key.Line-1, key.Column-1), indentLines(stub, indent))
+	return nil
+}
+
+// appendItem appends the stub to the top-level list, e.g. the included features file
+func (s *specFile) appendItem(stub []string) {
+	end := len(s.lines)
+	for end > 0 && strings.TrimSpace(s.lines[end-1]) == "" {
+		end--
+	}
+	s.insert(end, stub)
+}
+
+// blockEnd returns the line after the block of the key, the trailing blank lines and comments are excluded
+func (s *specFile) blockEnd(line, column int) int {
+	end := line + 1
+	for i := line + 1; i < len(s.lines); i++ {
+		text := strings.TrimRight(s.lines[i], "\r")
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		if indent < column || (indent == column && !strings.HasPrefix(trimmed, "-")) {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+func (s *specFile) insert(at int, lines []string) {
+	result := append([]string{}, s.lines[:at]...)
+	result = append(result, lines...)
+	s.lines = append(result, s.lines[at:]...)
+}
+
+func (s *specFile) write() error {
+	content := strings.Join(s.lines, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(s.file); err == nil {
+		mode = info.Mode()
+	}
+	if err := os.WriteFile(s.file, []byte(content), mode); err != nil {
+		return eris.Wrapf(err, "failed to write the file %s", s.file)
+	}
+	return nil
+}
+
+// nextId increases the number of the largest ID in the module, e.g. UF011A -> UF012A
+func nextId(moduleIds []string, all map[string]bool) (string, error) {
+	var prefix, suffix string
+	width, number := 0, -1
+	for _, id := range moduleIds {
+		m := idPattern.FindStringSubmatch(id)
+		if m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil || n < number {
+			continue
+		}
+		prefix, suffix, width, number = m[1], m[3], len(m[2]), n
+	}
+	if number < 0 {
+		return "", eris.New("no numbered feature ID in the module, specify the ID by --id")
+	}
+	for {
+		number++
+		id := fmt.Sprintf("%s%0*d%s", prefix, width, number, suffix)
+		if !all[id] {
+			return id, nil
+		}
+	}
+}
+
+// featureIds returns the IDs of the features in any level, e.g. the included features file
+func featureIds(node *yaml.Node) []string {
+	ids := []string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Kind == yaml.MappingNode && node.Content[i].Value == "features" {
+			ids = append(ids, itemIds(node.Content[i+1])...)
+		}
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+		// list of the features or modules
+		ids = append(ids, itemIds(node.Content[0])...)
+	}
+	for _, item := range node.Content {
+		ids = append(ids, featureIds(item)...)
+	}
+	return ids
+}
+
+// itemIds returns the IDs of the items in the list
+func itemIds(list *yaml.Node) []string {
+	ids := []string{}
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return ids
+	}
+	for _, item := range list.Content {
+		if id := mappingValue(item, "id"); id != nil {
+			ids = append(ids, docb.ToStrArray(nodeValue(id))...)
+		}
+	}
+	return ids
+}
+
+func nodeValue(node *yaml.Node) interface{} {
+	var v interface{}
+	_ = node.Decode(&v)
+	return v
+}
+
+func indentLines(lines []string, indent string) []string {
+	result := []string{}
+	for _, line := range lines {
+		result = append(result, indent+line)
+	}
+	return result
+}
+
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+	return nil
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingString(node *yaml.Node, key string) string {
+	if value := mappingValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
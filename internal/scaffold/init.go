@@ -0,0 +1,110 @@
+// project skeleton and feature stubs for the new comers
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+	"github.com/zrs01/pst/internal/docb"
+	"github.com/zrs01/pst/pkg/docx"
+)
+
+//go:embed skeleton/pst.yml skeleton/config.yml skeleton/specs
+var skeleton embed.FS
+
+//go:embed skeleton/feature.yml
+var featureStub string
+
+// files generated instead of embedded
+const (
+	templateFile = "template.docx"
+	imageFile    = "images/sample.png"
+)
+
+// Init creates the manifest, config, template, sample spec and images directory in the directory,
+// the existing files are kept unless force
+func Init(dir string, force bool) error {
+	files := map[string][]byte{}
+	err := fs.WalkDir(skeleton, "skeleton", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := skeleton.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel("skeleton", path)
+		files[rel] = content
+		return nil
+	})
+	if err != nil {
+		return eris.Wrap(err, "failed to read the skeleton")
+	}
+	if files[templateFile], err = newTemplate(); err != nil {
+		return err
+	}
+	if files[imageFile], err = newImage(); err != nil {
+		return err
+	}
+
+	if !force {
+		for name := range files {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return eris.Errorf("%s already exists, use --force to overwrite", filepath.Join(dir, name))
+			}
+		}
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+			return eris.Wrapf(err, "failed to create the directory of %s", file)
+		}
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return eris.Wrapf(err, "failed to write %s", file)
+		}
+		logrus.Infof("created %s", file)
+	}
+	return nil
+}
+
+// newTemplate returns a blank document with the styles of the tables, the styles could be customized in
+// Word afterward
+func newTemplate() ([]byte, error) {
+	builder, err := docx.NewDocumentBuilder("")
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create the template")
+	}
+	docb.AddStyles(builder)
+	var buf bytes.Buffer
+	if err := builder.Document.Save(&buf); err != nil {
+		return nil, eris.Wrap(err, "failed to create the template")
+	}
+	return buf.Bytes(), nil
+}
+
+// newImage returns a placeholder of the screen capture
+func newImage() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 240))
+	for x := 0; x < 400; x++ {
+		for y := 0; y < 240; y++ {
+			c := color.RGBA{0xe9, 0xec, 0xef, 0xff}
+			if x < 2 || y < 2 || x > 397 || y > 237 || y < 32 {
+				c = color.RGBA{0xce, 0xd4, 0xda, 0xff}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, eris.Wrap(err, "failed to create the sample image")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,54 @@
+package watch
+
+import (
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/zrs01/pst/internal/docb"
+)
+
+// SpecUnits returns the units of the build with the arguments of docb.Build, one unit per input file
+// if the documents are split by file, otherwise one unit for the whole build
+func SpecUnits(name, cfile, ifile, ofile, tfile string, opts docb.Options) ([]Unit, error) {
+	for _, file := range strings.Split(ifile, ",") {
+		if strings.TrimSpace(file) == docb.StdStream {
+			return nil, eris.New("cannot watch stdin")
+		}
+	}
+	if opts.Split != docb.SplitFile {
+		return []Unit{specUnit(name, cfile, ifile, ofile, tfile, opts)}, nil
+	}
+
+	files, err := docb.ResolveInputFiles(ifile)
+	if err != nil {
+		return nil, err
+	}
+	units := []Unit{}
+	for _, file := range files {
+		unit := specUnit(name+" ("+file+")", cfile, file, ofile, tfile, opts)
+		deps := unit.Deps
+		// watch the directories of the whole input as well, a file created there is a new unit
+		unit.Deps = func() ([]string, error) {
+			files, err := deps()
+			paths, perr := docb.InputPaths(ifile)
+			if err == nil {
+				err = perr
+			}
+			return append(files, paths...), err
+		}
+		units = append(units, unit)
+	}
+	return units, nil
+}
+
+func specUnit(name, cfile, ifile, ofile, tfile string, opts docb.Options) Unit {
+	return Unit{
+		Name: name,
+		Build: func() error {
+			return docb.Build(cfile, ifile, ofile, tfile, opts)
+		},
+		Deps: func() ([]string, error) {
+			return docb.Dependencies(cfile, ifile, tfile, opts)
+		},
+	}
+}
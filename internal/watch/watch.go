@@ -0,0 +1,180 @@
+// rebuild the documents when the files change
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDebounce is the quiet period after the last change before rebuilding
+const DefaultDebounce = 300 * time.Millisecond
+
+// Unit is the smallest piece to rebuild, e.g. a target of the manifest or an input file in split mode
+type Unit struct {
+	Name  string
+	Build func() error
+	Deps  func() ([]string, error) // files read by the build
+}
+
+// Units returns the units to watch, called again after every change as the files may be added or removed
+type Units func() ([]Unit, error)
+
+type watcher struct {
+	units    Units
+	debounce time.Duration
+	fsw      *fsnotify.Watcher
+	dirs     map[string]bool            // directories watched
+	deps     map[string]map[string]bool // unit name -> absolute paths of the dependencies
+	extras   []string                   // files always watched, e.g. the manifest
+}
+
+// Run builds all units and rebuilds the affected units on change until interrupted. The errors of the
+// build are logged without exiting so that the writer can fix and save again.
+func Run(units Units, debounce time.Duration, extras ...string) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return eris.Wrap(err, "failed to create the file watcher")
+	}
+	defer fsw.Close()
+
+	w := &watcher{units: units, debounce: debounce, fsw: fsw, dirs: map[string]bool{}, deps: map[string]map[string]bool{}}
+	for _, extra := range extras {
+		w.extras = append(w.extras, absPath(extra))
+	}
+	w.rebuild(nil)
+	return w.loop(fsw.Events, fsw.Errors)
+}
+
+// loop collects the changed files and rebuilds once no more change comes within the debounce
+func (w *watcher) loop(events <-chan fsnotify.Event, errors <-chan error) error {
+	changed := map[string]bool{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			logrus.Debugf("%s %s", event.Op, event.Name)
+			changed[absPath(event.Name)] = true
+			timer.Reset(w.debounce)
+		case err, ok := <-errors:
+			if !ok {
+				return nil
+			}
+			logrus.Warn(eris.Wrap(err, "file watcher error"))
+		case <-timer.C:
+			w.rebuild(changed)
+			changed = map[string]bool{}
+		}
+	}
+}
+
+// rebuild builds the units depending on the changed files, all units if changed is nil
+func (w *watcher) rebuild(changed map[string]bool) {
+	all := changed == nil
+	for _, extra := range w.extras {
+		if changed[extra] {
+			all = true
+		}
+	}
+
+	units, err := w.units()
+	if err != nil {
+		logrus.Error(eris.ToString(err, false))
+		w.watch(w.extras)
+		return
+	}
+
+	deps := map[string]map[string]bool{}
+	watched := append([]string{}, w.extras...)
+	for _, unit := range units {
+		files, err := unit.Deps()
+		if err != nil {
+			// still watch the files found so far, the error is reported by the build
+			logrus.Debug(eris.ToString(err, false))
+		}
+		deps[unit.Name] = map[string]bool{}
+		for _, file := range files {
+			path := absPath(file)
+			deps[unit.Name][path] = true
+			watched = append(watched, path)
+		}
+
+		if all || w.affected(unit.Name, deps[unit.Name], changed) {
+			start := time.Now()
+			logrus.Infof("building %s", unit.Name)
+			if err := unit.Build(); err != nil {
+				logrus.Error(eris.ToString(err, false))
+			} else {
+				logrus.Infof("built %s in %s", unit.Name, time.Since(start).Round(time.Millisecond))
+			}
+		}
+	}
+	w.deps = deps
+	w.watch(watched)
+	logrus.Info("watching for changes...")
+}
+
+// affected checks if the unit is new, has the dependencies added or removed, e.g. a spec file created
+// in the input directory or excluded by the .pstignore, or depends on any changed file
+func (w *watcher) affected(name string, deps map[string]bool, changed map[string]bool) bool {
+	previous, ok := w.deps[name]
+	if !ok || len(previous) != len(deps) {
+		return true
+	}
+	for path := range deps {
+		if !previous[path] {
+			return true
+		}
+	}
+	for path := range changed {
+		if deps[path] {
+			return true
+		}
+	}
+	return false
+}
+
+// watch adds the directories of the files to the watcher, directory is watched instead of the file
+// since editors usually save by replacing the file
+func (w *watcher) watch(files []string) {
+	dirs := []string{}
+	for _, file := range files {
+		dir := file
+		if info, err := os.Stat(file); err != nil || !info.IsDir() {
+			dir = filepath.Dir(file)
+		}
+		if !w.dirs[dir] {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsw.Add(dir); err != nil {
+			logrus.Warn(eris.Wrapf(err, "failed to watch %s", dir))
+			continue
+		}
+		w.dirs[dir] = true
+	}
+}
+
+func absPath(file string) string {
+	if path, err := filepath.Abs(file); err == nil {
+		return path
+	}
+	return file
+}
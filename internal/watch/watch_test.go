@@ -0,0 +1,181 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestAffected(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string // nil if the unit is new
+		deps     []string
+		changed  []string
+		want     bool
+	}{
+		{"new unit", nil, []string{"/a.yml"}, nil, true},
+		{"dependency changed", []string{"/a.yml", "/b.yml"}, []string{"/a.yml", "/b.yml"}, []string{"/b.yml"}, true},
+		{"other file changed", []string{"/a.yml"}, []string{"/a.yml"}, []string{"/c.yml"}, false},
+		{"file created", []string{"/specs", "/specs/a.yml"}, []string{"/specs", "/specs/a.yml", "/specs/b.yml"}, []string{"/specs/b.yml"}, true},
+		{"file ignored", []string{"/specs", "/specs/a.yml", "/specs/b.yml"}, []string{"/specs", "/specs/a.yml"}, []string{"/.pstignore"}, true},
+		{"file replaced", []string{"/a.yml", "/b.yml"}, []string{"/a.yml", "/c.yml"}, nil, true},
+		{"file created not input", []string{"/specs", "/specs/a.yml"}, []string{"/specs", "/specs/a.yml"}, []string{"/specs/notes.txt"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &watcher{deps: map[string]map[string]bool{}}
+			if tt.previous != nil {
+				w.deps["unit"] = set(tt.previous)
+			}
+			if got := w.affected("unit", set(tt.deps), set(tt.changed)); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "specs", "a.yml"))
+	writeFile(t, filepath.Join(dir, "other.yml"))
+
+	built := map[string]int{}
+	w := newTestWatcher(t, func() ([]Unit, error) {
+		return []Unit{
+			dirUnit(filepath.Join(dir, "specs"), built),
+			dirUnit(filepath.Join(dir, "other.yml"), built),
+		}, nil
+	}, 0)
+
+	w.rebuild(nil)
+	assertBuilt(t, built, map[string]int{"specs": 1, "other.yml": 1})
+	if !w.dirs[filepath.Join(dir, "specs")] {
+		t.Errorf("input directory not watched: %v", w.dirs)
+	}
+
+	// a spec created in the watched input directory is a new dependency
+	writeFile(t, filepath.Join(dir, "specs", "b.yml"))
+	w.rebuild(set([]string{filepath.Join(dir, "specs", "b.yml")}))
+	assertBuilt(t, built, map[string]int{"specs": 2, "other.yml": 1})
+
+	w.rebuild(set([]string{filepath.Join(dir, "other.yml")}))
+	assertBuilt(t, built, map[string]int{"specs": 2, "other.yml": 2})
+
+	w.rebuild(set([]string{filepath.Join(dir, "unknown.yml")}))
+	assertBuilt(t, built, map[string]int{"specs": 2, "other.yml": 2})
+}
+
+func TestDebounce(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.yml")
+	writeFile(t, file)
+
+	builds := make(chan string, 10)
+	w := newTestWatcher(t, func() ([]Unit, error) {
+		return []Unit{{
+			Name:  "a",
+			Build: func() error { builds <- "a"; return nil },
+			Deps:  func() ([]string, error) { return []string{file}, nil },
+		}}, nil
+	}, 50*time.Millisecond)
+	w.rebuild(nil)
+	<-builds
+
+	events := make(chan fsnotify.Event)
+	done := make(chan error)
+	go func() { done <- w.loop(events, make(chan error)) }()
+
+	// the changes within the debounce are built once
+	for i := 0; i < 3; i++ {
+		events <- fsnotify.Event{Name: file, Op: fsnotify.Write}
+		time.Sleep(10 * time.Millisecond)
+	}
+	events <- fsnotify.Event{Name: file, Op: fsnotify.Chmod}
+	if n := countBuilds(builds, 300*time.Millisecond); n != 1 {
+		t.Errorf("got %d builds, want 1", n)
+	}
+
+	// chmod only is ignored
+	events <- fsnotify.Event{Name: file, Op: fsnotify.Chmod}
+	if n := countBuilds(builds, 150*time.Millisecond); n != 0 {
+		t.Errorf("got %d builds after chmod, want 0", n)
+	}
+
+	close(events)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestWatcher(t *testing.T, units Units, debounce time.Duration) *watcher {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { fsw.Close() })
+	return &watcher{units: units, debounce: debounce, fsw: fsw, dirs: map[string]bool{}, deps: map[string]map[string]bool{}}
+}
+
+// dirUnit depends on the path and the files in it if it is a directory
+func dirUnit(path string, built map[string]int) Unit {
+	name := filepath.Base(path)
+	return Unit{
+		Name:  name,
+		Build: func() error { built[name]++; return nil },
+		Deps: func() ([]string, error) {
+			deps := []string{path}
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return deps, nil
+			}
+			for _, entry := range entries {
+				deps = append(deps, filepath.Join(path, entry.Name()))
+			}
+			return deps, nil
+		},
+	}
+}
+
+func countBuilds(builds chan string, wait time.Duration) int {
+	n := 0
+	timeout := time.After(wait)
+	for {
+		select {
+		case <-builds:
+			n++
+		case <-timeout:
+			return n
+		}
+	}
+}
+
+func assertBuilt(t *testing.T, got, want map[string]int) {
+	t.Helper()
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("%s built %d times, want %d", name, got[name], n)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func set(paths []string) map[string]bool {
+	m := map[string]bool{}
+	for _, path := range paths {
+		m[path] = true
+	}
+	return m
+}
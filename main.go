@@ -16,12 +16,22 @@ func main() {
 	cliapp.Name = "pst"
 	cliapp.Usage = "Program specfication tool"
 	cliapp.Version = version
-	cliapp.Commands = []*cli.Command{}
+	cliapp.Commands = []*cli.Command{
+		buildCommand(),
+		watchCommand(),
+		serveCommand(),
+		lspCommand(),
+		initCommand(),
+		newCommand(),
+		lintCommand(),
+		statsCommand(),
+		importResultsCommand(),
+		importOpenAPICommand(),
+	}
 
 	debug := false
-	var ifile, ofile, cfile, dfile string
 
-	cliapp.Flags = []cli.Flag{
+	cliapp.Flags = append([]cli.Flag{
 		&cli.BoolFlag{
 			Name:        "debug",
 			Aliases:     []string{"d"},
@@ -29,41 +39,22 @@ func main() {
 			Required:    false,
 			Destination: &debug,
 		},
-		&cli.StringFlag{
-			Name:        "config",
-			Aliases:     []string{"c"},
-			Usage:       "config file",
-			Required:    false,
-			Destination: &cfile,
-		},
-		&cli.StringFlag{
-			Name:        "input",
-			Aliases:     []string{"i"},
-			Usage:       "input file",
-			Required:    true,
-			Destination: &ifile,
-		},
-		&cli.StringFlag{
-			Name:        "output",
-			Aliases:     []string{"o"},
-			Usage:       "output file",
-			Required:    true,
-			Destination: &ofile,
-		},
-		&cli.StringFlag{
-			Name:        "document",
-			Aliases:     []string{"m"},
-			Usage:       "existing .docx file",
-			Required:    false,
-			Destination: &dfile,
-		},
-	}
+	}, specFlags()...)
 	cliapp.Action = func(ctx *cli.Context) error {
+		args, err := newSpecArgs(ctx)
+		if err != nil {
+			return err
+		}
+		// not marked as required, otherwise the sub-commands require them as well
+		if args.ifile == "" || args.ofile == "" {
+			return eris.New(`required flags "input" and "output" are not set`)
+		}
 		// return converter.Build(cfile, ifile, ofile, dfile)
-		return docb.Build(cfile, ifile, ofile, dfile)
+		return docb.Build(args.cfile, args.ifile, args.ofile, args.dfile, args.options)
 	}
 
 	if err := cliapp.Run(os.Args); err != nil {
 		logrus.Error(eris.ToString(err, debug))
+		os.Exit(1)
 	}
 }
@@ -1,10 +1,12 @@
 // document constructor
-package docb
+
+package docx
 
 import (
+	"fmt"
 	"strings"
 
-	"baliance.com/gooxml"
+	"baliance.com/gooxml/color"
 	"baliance.com/gooxml/document"
 	"github.com/rotisserie/eris"
 	"github.com/shomali11/util/xstrings"
@@ -19,9 +21,14 @@ type builder interface {
 }
 
 type Configuration struct {
-	FontFamily string
-	FontSize   int
-	ImagePath  string
+	FontFamily     string
+	FontSize       int
+	CodeFontFamily string // font of the `code` in the text
+	ImagePath      string
+	Bookmarks      map[string]string // word in the cell text -> bookmark, rendered as internal hyperlink
+	PageRefs       bool              // add the page number after the word having bookmark, e.g. UF011A (page 12)
+	numbering      *numbering
+	shadings       map[string]color.Color // paragraph style -> background of the cell
 }
 
 /* -------------------------------------------------------------------------- */
@@ -36,8 +43,11 @@ type DocumentBuilder struct {
 func NewDocumentBuilder(file string, cfg ...Configuration) (*DocumentBuilder, error) {
 	// override the options if is is provided
 	c := &Configuration{
-		FontFamily: "Arial",
-		FontSize:   10,
+		FontFamily:     "Arial",
+		FontSize:       10,
+		CodeFontFamily: "Courier New",
+		numbering:      &numbering{},
+		shadings:       map[string]color.Color{},
 	}
 	if len(cfg) > 0 {
 		if xstrings.IsNotBlank(cfg[0].FontFamily) {
@@ -46,7 +56,12 @@ func NewDocumentBuilder(file string, cfg ...Configuration) (*DocumentBuilder, er
 		if cfg[0].FontSize > 0 {
 			c.FontSize = cfg[0].FontSize
 		}
+		if xstrings.IsNotBlank(cfg[0].CodeFontFamily) {
+			c.CodeFontFamily = cfg[0].CodeFontFamily
+		}
 		c.ImagePath = cfg[0].ImagePath
+		c.Bookmarks = cfg[0].Bookmarks
+		c.PageRefs = cfg[0].PageRefs
 	}
 
 	// create document object at once for outsider customization
@@ -70,8 +85,6 @@ func NewDocumentBuilder(file string, cfg ...Configuration) (*DocumentBuilder, er
 		// 	},
 		// }
 	}
-	// adjust the default settings
-	fixBulletIndentation(doc)
 	return &DocumentBuilder{Document: doc, config: c}, nil
 }
 
@@ -98,7 +111,8 @@ func (d *DocumentBuilder) Build() {
 }
 
 /* -------------------------------- UTILITIES ------------------------------- */
-func toStrArray(v interface{}) []string {
+// ToStrArray converts the string, string array or other value to string array
+func ToStrArray(v interface{}) []string {
 	text := []string{}
 	switch t := v.(type) {
 	case string:
@@ -107,22 +121,13 @@ func toStrArray(v interface{}) []string {
 		text = append(text, t...)
 	case []interface{}:
 		for _, value := range t {
-			content := strings.TrimSpace(value.(string))
+			content := strings.TrimSpace(fmt.Sprint(value))
 			text = append(text, content)
 		}
+	case nil:
+	default:
+		// number or boolean, e.g. from JSON or TOML
+		text = []string{fmt.Sprint(t)}
 	}
 	return text
 }
-
-func fixBulletIndentation(doc *document.Document) {
-	const indentStart = 400
-	const indentDelta = 400
-	const hangingIndent = 360
-
-	abns := doc.Numbering.X().AbstractNum[0]
-	for i, lvl := range abns.Lvl {
-		indent := int64(i*indentDelta + indentStart)
-		lvl.PPr.Ind.LeftAttr.Int64 = gooxml.Int64(indent)
-		lvl.PPr.Ind.HangingAttr.ST_UnsignedDecimalNumber = gooxml.Uint64(uint64(hangingIndent))
-	}
-}
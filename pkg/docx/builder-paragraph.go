@@ -1,4 +1,4 @@
-package docb
+package docx
 
 import (
 	"path"
@@ -21,6 +21,8 @@ type ParagraphBuilder struct {
 	pageBreak bool
 	lineBreak bool
 	images    []*ImageProperty
+	bookmarks []string
+	contents  []inlineContent
 	// 	imageFilePath string
 	// 	imageWidth    int
 }
@@ -35,7 +37,7 @@ func (p *ParagraphBuilder) SetStyle(s string) *ParagraphBuilder {
 }
 
 func (p *ParagraphBuilder) SetText(s interface{}) *ParagraphBuilder {
-	p.text = toStrArray(s)
+	p.text = ToStrArray(s)
 	return p
 }
 
@@ -54,6 +56,42 @@ func (p *ParagraphBuilder) SetAlignment(align wml.ST_Jc) *ParagraphBuilder {
 	return p
 }
 
+// AddBookmark adds the bookmark as the target of the internal hyperlinks
+func (p *ParagraphBuilder) AddBookmark(name string) *ParagraphBuilder {
+	p.bookmarks = append(p.bookmarks, name)
+	return p
+}
+
+// AddText adds the text after the text with its own font and style, e.g.
+// AddText("mandatory", func(rp *RunProperty) { rp.SetItalic().SetColor(color.Red) })
+func (p *ParagraphBuilder) AddText(text string, set ...func(*RunProperty)) *ParagraphBuilder {
+	rp := newRunProperty()
+	for _, s := range set {
+		s(rp)
+	}
+	p.contents = append(p.contents, inlineContent{text: text, style: rp})
+	return p
+}
+
+// AddHyperlink adds the link to the external url after the text
+func (p *ParagraphBuilder) AddHyperlink(text, url string) *ParagraphBuilder {
+	p.contents = append(p.contents, inlineContent{text: text, url: url})
+	return p
+}
+
+// AddInternalLink adds the link to the bookmark after the text
+func (p *ParagraphBuilder) AddInternalLink(text, bookmark string) *ParagraphBuilder {
+	p.contents = append(p.contents, inlineContent{text: text, bookmark: bookmark})
+	return p
+}
+
+// AddReference adds the field of the text or page number of the bookmark after the text,
+// e.g. SetText("see UF011A on page ").AddReference("F_UF011A", RefPage)
+func (p *ParagraphBuilder) AddReference(bookmark string, field ReferenceField) *ParagraphBuilder {
+	p.contents = append(p.contents, inlineContent{bookmark: bookmark, field: field})
+	return p
+}
+
 func (p *ParagraphBuilder) AddImage(set func(*ImageProperty)) *ParagraphBuilder {
 	i := newImageProperty()
 	set(i)
@@ -72,13 +110,21 @@ func (p *ParagraphBuilder) Build() {
 		paragraph.Properties().SetAlignment(p.alignment)
 	}
 
-	for i, s := range p.text {
-		run := paragraph.AddRun()
-		run.AddText(s)
-		if i == len(p.text)-1 && p.lineBreak {
-			run.AddBreak()
+	for _, name := range p.bookmarks {
+		paragraph.AddBookmark(name)
+	}
+
+	for _, s := range p.text {
+		for _, r := range addInlineRuns(p.config, *paragraph, s, nil) {
+			r.write()
 		}
 	}
+	for _, content := range p.contents {
+		content.add(*paragraph, func(document.Run) {})
+	}
+	if p.lineBreak {
+		paragraph.AddRun().AddBreak()
+	}
 
 	for _, img := range p.images {
 		// image should relative to input file
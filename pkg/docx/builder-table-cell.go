@@ -0,0 +1,525 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+	"github.com/shomali11/util/xconditions"
+)
+
+type CellBuilder struct {
+	config          *Configuration
+	document        *document.Document
+	cell            *document.Cell
+	builder         []builder
+	fontFamily      string
+	fontSize        int
+	bold            *bool // false turns off the bold of the style
+	italic          bool
+	underline       wml.ST_Underline
+	color           *color.Color
+	highlight       wml.ST_HighlightColor
+	widthPercent    float64
+	colspan         int
+	rowspan         int
+	gridCol         int          // grid column of the cell in the table
+	merged          *CellBuilder // cell merged above, the cell only continues it
+	bullet          *document.NumberingDefinition
+	list            ListKind
+	items           []listItem
+	code            *string
+	codeProperty    *CodeProperty
+	backgroundColor *color.Color
+	borders         *Borders
+	alignment       wml.ST_Jc
+	style           string
+	verticalAlign   wml.ST_VerticalJc
+	margins         []measurement.Distance // top, right, bottom and left
+	textDirection   wml.ST_TextDirection
+	noLinks         bool
+	bookmarks       []string
+	contents        []inlineContent
+}
+
+func newCellBuilder(cfg *Configuration, doc *document.Document, c document.Cell) *CellBuilder {
+	return &CellBuilder{config: cfg, document: doc, cell: &c}
+}
+
+func (c *CellBuilder) AddParagraph(nextBuilder ...func(*ParagraphBuilder)) *CellBuilder {
+	p := newParagraphBuilder(c.config, c.document, c.cell.AddParagraph())
+	c.builder = append(c.builder, p)
+	if len(nextBuilder) > 0 {
+		nextBuilder[0](p)
+	}
+	return c
+}
+
+func (c *CellBuilder) SetFontFamily(ff string) *CellBuilder {
+	c.fontFamily = ff
+	return c
+}
+
+func (c *CellBuilder) SetFontSize(fs int) *CellBuilder {
+	c.fontSize = fs
+	return c
+}
+
+func (c *CellBuilder) SetBold() *CellBuilder {
+	c.bold = gooxml.Bool(true)
+	return c
+}
+
+// SetNotBold turns off the bold of the style, e.g. the value in the header row
+func (c *CellBuilder) SetNotBold() *CellBuilder {
+	c.bold = gooxml.Bool(false)
+	return c
+}
+
+func (c *CellBuilder) SetItalic() *CellBuilder {
+	c.italic = true
+	return c
+}
+
+func (c *CellBuilder) SetUnderline(style wml.ST_Underline) *CellBuilder {
+	c.underline = style
+	return c
+}
+
+// SetColor sets the color of the text
+func (c *CellBuilder) SetColor(color color.Color) *CellBuilder {
+	c.color = &color
+	return c
+}
+
+func (c *CellBuilder) SetHighlight(h wml.ST_HighlightColor) *CellBuilder {
+	c.highlight = h
+	return c
+}
+
+func (c *CellBuilder) SetWidthPercent(w float64) *CellBuilder {
+	c.widthPercent = w
+	return c
+}
+
+func (c *CellBuilder) SetColspan(cols int) *CellBuilder {
+	c.colspan = cols
+	return c
+}
+
+// SetRowspan merges the cell with the cells below it, the rows add the cells of the merge at the same column
+func (c *CellBuilder) SetRowspan(rows int) *CellBuilder {
+	c.rowspan = rows
+	return c
+}
+
+// span returns the grid columns of the cell
+func (c *CellBuilder) span() int {
+	if c.colspan > 1 {
+		return c.colspan
+	}
+	return 1
+}
+
+// SetText sets the text of the cell, the nested sequences and the lines starting with - or 1. are the list items
+func (c *CellBuilder) SetText(v interface{}) *CellBuilder {
+	c.items = toListItems(v)
+	return c
+}
+
+// SetCode sets the code block instead of the text, the whitespace is preserved and the keywords are highlighted
+func (c *CellBuilder) SetCode(code string, set ...func(*CodeProperty)) *CellBuilder {
+	c.code = &code
+	c.codeProperty = newCodeProperty()
+	for _, s := range set {
+		s(c.codeProperty)
+	}
+	return c
+}
+
+// SetBullet numbers the lines by the definition if there is more than one line.
+//
+// Deprecated: use SetList, the definition is shared with other lists.
+func (c *CellBuilder) SetBullet(b *document.NumberingDefinition) *CellBuilder {
+	c.bullet = b
+	return c
+}
+
+// SetList sets the numbering of the lines if there is more than one line, the items of the list markers
+// in the text have their own numbering
+func (c *CellBuilder) SetList(kind ListKind) *CellBuilder {
+	c.list = kind
+	return c
+}
+
+func (c *CellBuilder) SetBackgroundColor(color color.Color) *CellBuilder {
+	c.backgroundColor = &color
+	return c
+}
+
+func (c *CellBuilder) SetBorders(callback func(*Borders)) *CellBuilder {
+	c.borders = &Borders{}
+	callback(c.borders)
+	return c
+}
+
+func (c *CellBuilder) SetAlignment(align wml.ST_Jc) *CellBuilder {
+	c.alignment = align
+	return c
+}
+
+func (c *CellBuilder) SetVerticalAlignment(align wml.ST_VerticalJc) *CellBuilder {
+	c.verticalAlign = align
+	return c
+}
+
+// SetMargins sets the space between the borders and the text of the cell
+func (c *CellBuilder) SetMargins(top, right, bottom, left measurement.Distance) *CellBuilder {
+	c.margins = []measurement.Distance{top, right, bottom, left}
+	return c
+}
+
+// SetTextDirection rotates the text, e.g. wml.ST_TextDirectionBtLr for the narrow header column
+func (c *CellBuilder) SetTextDirection(dir wml.ST_TextDirection) *CellBuilder {
+	c.textDirection = dir
+	return c
+}
+
+// SetStyle sets the paragraph style of the text, the font, size and bold of the style are used unless
+// they are set to the cell, and its shading fills the cell unless the background color is set, see
+// DocumentBuilder.AddStyle
+func (c *CellBuilder) SetStyle(id string) *CellBuilder {
+	c.style = id
+	return c
+}
+
+// shading returns the background color of the cell, or of its style
+func (c *CellBuilder) shading() *color.Color {
+	if c.backgroundColor != nil {
+		return c.backgroundColor
+	}
+	if shading, ok := c.config.shadings[c.style]; ok {
+		return &shading
+	}
+	return nil
+}
+
+// DisableLinks keeps the IDs in the text as plain text, e.g. the ID of the feature itself
+func (c *CellBuilder) DisableLinks() *CellBuilder {
+	c.noLinks = true
+	return c
+}
+
+// AddBookmark adds the bookmark to the first line as the target of the internal hyperlinks
+func (c *CellBuilder) AddBookmark(name string) *CellBuilder {
+	c.bookmarks = append(c.bookmarks, name)
+	return c
+}
+
+// AddText adds the text to the last line with its own font and style, the others are the same as the cell
+func (c *CellBuilder) AddText(text string, set ...func(*RunProperty)) *CellBuilder {
+	rp := newRunProperty()
+	for _, s := range set {
+		s(rp)
+	}
+	c.contents = append(c.contents, inlineContent{text: text, style: rp})
+	return c
+}
+
+// AddHyperlink adds the link to the external url after the text
+func (c *CellBuilder) AddHyperlink(text, url string) *CellBuilder {
+	c.contents = append(c.contents, inlineContent{text: text, url: url})
+	return c
+}
+
+// AddInternalLink adds the link to the bookmark after the text
+func (c *CellBuilder) AddInternalLink(text, bookmark string) *CellBuilder {
+	c.contents = append(c.contents, inlineContent{text: text, bookmark: bookmark})
+	return c
+}
+
+// AddReference adds the field of the text or page number of the bookmark after the text
+func (c *CellBuilder) AddReference(bookmark string, field ReferenceField) *CellBuilder {
+	c.contents = append(c.contents, inlineContent{bookmark: bookmark, field: field})
+	return c
+}
+
+func (c *CellBuilder) Build() {
+	if c.merged != nil {
+		c.buildMerged()
+		return
+	}
+	if c.borders != nil {
+		b := c.cell.Properties().Borders()
+		if c.borders.Top != nil {
+			b.SetTop(c.borders.Top.Style, c.borders.Top.Color, c.borders.Top.Thickness)
+		}
+		if c.borders.Right != nil {
+			b.SetRight(c.borders.Right.Style, c.borders.Right.Color, c.borders.Right.Thickness)
+		}
+		if c.borders.Bottom != nil {
+			b.SetBottom(c.borders.Bottom.Style, c.borders.Bottom.Color, c.borders.Bottom.Thickness)
+		}
+		if c.borders.Left != nil {
+			b.SetLeft(c.borders.Left.Style, c.borders.Left.Color, c.borders.Left.Thickness)
+		}
+		if c.borders.InsideHorizontal != nil {
+			b.SetInsideHorizontal(c.borders.InsideHorizontal.Style, c.borders.InsideHorizontal.Color, c.borders.InsideHorizontal.Thickness)
+		}
+		if c.borders.InsideVertical != nil {
+			b.SetInsideVertical(c.borders.InsideVertical.Style, c.borders.InsideVertical.Color, c.borders.InsideVertical.Thickness)
+		}
+	}
+
+	if c.widthPercent > 0 {
+		c.cell.Properties().SetWidthPercent(c.widthPercent)
+	}
+
+	if c.colspan > 0 {
+		c.cell.Properties().SetColumnSpan(c.colspan)
+	}
+
+	if c.rowspan > 1 {
+		c.cell.Properties().SetVerticalMerge(wml.ST_MergeRestart)
+	}
+
+	if c.verticalAlign != wml.ST_VerticalJcUnset {
+		c.cell.Properties().SetVerticalAlignment(c.verticalAlign)
+	}
+
+	if len(c.margins) == 4 {
+		m := c.cell.Properties().Margins()
+		m.SetTop(c.margins[0])
+		m.SetRight(c.margins[1])
+		m.SetBottom(c.margins[2])
+		m.SetLeft(c.margins[3])
+	}
+
+	if c.textDirection != wml.ST_TextDirectionUnset {
+		td := wml.NewCT_TextDirection()
+		td.ValAttr = c.textDirection
+		c.cell.Properties().X().TextDirection = td
+	}
+
+	if bg := c.shading(); bg != nil {
+		c.cell.Properties().SetShading(wml.ST_ShdSolid, *bg, color.Auto)
+	}
+
+	paragraphs := len(c.cell.Paragraphs())
+	if c.code != nil {
+		c.buildCode()
+	} else if len(c.items) == 0 {
+		p := c.cell.AddParagraph()
+		p.AddRun().AddText("")
+	} else {
+		defs := map[ListKind]document.NumberingDefinition{}
+		prev := listItem{}
+		for _, item := range c.items {
+			p := c.cell.AddParagraph()
+			if c.alignment != wml.ST_JcUnset {
+				p.Properties().SetAlignment(c.alignment)
+			}
+			tab := ""
+			kind := c.itemKind(item)
+			if kind != ListNone {
+				def, ok := defs[kind]
+				if !ok || (kind == ListDecimal && prev.kind != ListDecimal && prev.level < item.level) {
+					// the numbered list restarts after the other lines or under the item of other list
+					def = c.config.numbering.definition(c.document, kind)
+					defs[kind] = def
+				}
+				p.SetNumberingDefinition(def)
+				p.SetNumberingLevel(item.level)
+			} else {
+				if c.bullet != nil && len(c.items) > 1 {
+					p.SetNumberingLevel(0)
+					p.SetNumberingDefinition(*c.bullet)
+					tab = "\t"
+				}
+				delete(defs, ListDecimal)
+			}
+			prev = listItem{level: item.level, kind: kind}
+			lines := strings.Split(item.text, "\\n")
+			for i, line := range lines {
+				line := strings.ReplaceAll(line, "\\t", "\t")
+				if i == 0 {
+					c.addRuns(p, tab+line, false)
+				} else {
+					// multi-line text
+					c.addRuns(p, line, true)
+				}
+			}
+		}
+	}
+
+	if added := c.cell.Paragraphs()[paragraphs:]; len(added) > 0 {
+		if c.style != "" {
+			for _, p := range added {
+				p.SetStyle(c.style)
+			}
+		}
+		for _, name := range c.bookmarks {
+			added[0].AddBookmark(name)
+		}
+		for _, content := range c.contents {
+			content.add(added[len(added)-1], c.setFont)
+		}
+	}
+
+	for _, builder := range c.builder {
+		builder.Build()
+	}
+}
+
+// buildMerged continues the merged cell, it has the width, borders and background of the merged cell and
+// an empty paragraph
+func (c *CellBuilder) buildMerged() {
+	m := c.merged
+	c.cell.Properties().SetVerticalMerge(wml.ST_MergeContinue)
+	if m.widthPercent > 0 {
+		c.cell.Properties().SetWidthPercent(m.widthPercent)
+	}
+	if m.colspan > 0 {
+		c.cell.Properties().SetColumnSpan(m.colspan)
+	}
+	if m.borders != nil {
+		b := c.cell.Properties().Borders()
+		if m.borders.Right != nil {
+			b.SetRight(m.borders.Right.Style, m.borders.Right.Color, m.borders.Right.Thickness)
+		}
+		if m.borders.Bottom != nil {
+			b.SetBottom(m.borders.Bottom.Style, m.borders.Bottom.Color, m.borders.Bottom.Thickness)
+		}
+		if m.borders.Left != nil {
+			b.SetLeft(m.borders.Left.Style, m.borders.Left.Color, m.borders.Left.Thickness)
+		}
+	}
+	if bg := m.shading(); bg != nil {
+		c.cell.Properties().SetShading(wml.ST_ShdSolid, *bg, color.Auto)
+	}
+	p := c.cell.AddParagraph()
+	if m.style != "" {
+		p.SetStyle(m.style)
+	}
+}
+
+// itemKind returns the numbering of the item, the items without the list marker follow the cell
+func (c *CellBuilder) itemKind(item listItem) ListKind {
+	if item.kind != ListNone {
+		return item.kind
+	}
+	if c.bullet != nil {
+		return ListNone
+	}
+	if c.list != ListNone && len(c.items) > 1 {
+		return c.list
+	}
+	if item.level > 0 {
+		return ListBullet
+	}
+	return ListNone
+}
+
+// addRuns adds the text with the inline formatting to the paragraph, the IDs having bookmark are linked to the heading
+func (c *CellBuilder) addRuns(p document.Paragraph, text string, lineBreak bool) {
+	bookmarks := c.config.Bookmarks
+	if c.noLinks {
+		bookmarks = nil
+	}
+	for i, r := range addInlineRuns(c.config, p, text, bookmarks) {
+		if r.style.code {
+			c.setStyle(r.run)
+		} else {
+			c.setFont(r.run)
+		}
+		if r.style.bold {
+			r.run.Properties().SetBold(true)
+		}
+		if r.linked {
+			setLinkStyle(r.run)
+		}
+		if i == 0 && lineBreak {
+			r.run.AddBreak()
+		}
+		r.write()
+	}
+}
+
+// setFont sets the font and style of the cell to the run, the paragraph style decides the ones not set to the cell
+func (c *CellBuilder) setFont(run document.Run) {
+	if c.style == "" || c.fontFamily != "" {
+		run.Properties().SetFontFamily(xconditions.IfThenElse(c.fontFamily != "", c.fontFamily, c.config.FontFamily).(string))
+	}
+	c.setStyle(run)
+}
+
+// setStyle sets the style of the cell other than the font family to the run, e.g. the inline code
+func (c *CellBuilder) setStyle(run document.Run) {
+	switch {
+	case c.bold != nil:
+		x := run.Properties().X()
+		x.B, x.BCs = onOff(*c.bold), onOff(*c.bold)
+	case c.style == "":
+		run.Properties().SetBold(false)
+	}
+	if c.style == "" || c.fontSize > 0 {
+		run.Properties().SetSize(measurement.Distance(xconditions.IfThenElse(c.fontSize > 0, c.fontSize, c.config.FontSize).(int)))
+	}
+	if c.italic {
+		run.Properties().SetItalic(true)
+	}
+	if c.color != nil {
+		run.Properties().SetColor(*c.color)
+	}
+	if c.underline != wml.ST_UnderlineUnset {
+		run.Properties().SetUnderline(c.underline, xcolor(c.color))
+	}
+	if c.highlight != wml.ST_HighlightColorUnset {
+		run.Properties().SetHighlight(c.highlight)
+	}
+}
+
+// buildCode adds the lines of the code in the code font, the line numbers are right aligned
+func (c *CellBuilder) buildCode() {
+	code := strings.TrimRight(strings.ReplaceAll(*c.code, "\r\n", "\n"), "\n")
+	if c.codeProperty.TabWidth > 0 {
+		code = strings.ReplaceAll(code, "\t", strings.Repeat(" ", c.codeProperty.TabWidth))
+	}
+	size := measurement.Distance(xconditions.IfThenElse(c.fontSize > 0, c.fontSize, c.config.FontSize).(int))
+	newRun := func(p document.Paragraph, text string) document.Run {
+		run := p.AddRun()
+		run.Properties().SetFontFamily(c.config.CodeFontFamily)
+		run.Properties().SetSize(size)
+		run.AddText(text)
+		return run
+	}
+
+	lines := highlight(code, c.codeProperty.Language)
+	width := len(fmt.Sprint(len(lines)))
+	for i, tokens := range lines {
+		p := c.cell.AddParagraph()
+		p.Properties().SetSpacing(0, 0)
+		if c.codeProperty.LineNumbers {
+			newRun(p, fmt.Sprintf("%*d  ", width, i+1)).Properties().SetColor(lineNumberColor)
+		}
+		if len(tokens) == 0 {
+			newRun(p, "")
+		}
+		for _, token := range tokens {
+			run := newRun(p, token.text)
+			if tc, ok := tokenColors[token.kind]; ok {
+				run.Properties().SetColor(tc)
+			}
+			switch token.kind {
+			case tokenKeyword:
+				run.Properties().SetBold(true)
+			case tokenComment:
+				run.Properties().SetItalic(true)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package docx
+
+import (
+	"testing"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+func TestCellSetStyle(t *testing.T) {
+	tests := []struct {
+		name         string
+		cell         CellBuilder
+		bold, italic string
+	}{
+		{"no style", CellBuilder{}, "unset", "unset"},
+		{"style decides", CellBuilder{style: "TableHeader"}, "unset", "unset"},
+		{"bold", CellBuilder{bold: gooxml.Bool(true)}, "true", "unset"},
+		{"not bold over style", CellBuilder{style: "TableHeader", bold: gooxml.Bool(false)}, "false", "unset"},
+		{"italic", CellBuilder{style: "TableHeader", italic: true}, "unset", "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := document.New().AddParagraph().AddRun()
+			tt.cell.config = &Configuration{FontSize: 10}
+			tt.cell.setStyle(run)
+			x := run.X().RPr
+			if x == nil {
+				x = wml.NewCT_RPr()
+			}
+			assertOnOff(t, "b", x.B, tt.bold)
+			assertOnOff(t, "bCs", x.BCs, tt.bold)
+			assertOnOff(t, "i", x.I, tt.italic)
+		})
+	}
+}
+
+func TestCellProperties(t *testing.T) {
+	d, err := NewDocumentBuilder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table *TableBuilder
+	d.AddTable(func(tb *TableBuilder) {
+		table = tb
+		tb.AddRow(func(r *RowBuilder) {
+			r.AddCell(func(c *CellBuilder) {
+				c.SetText("a").
+					SetVerticalAlignment(wml.ST_VerticalJcCenter).
+					SetMargins(1*measurement.Point, 2*measurement.Point, 3*measurement.Point, 4*measurement.Point).
+					SetTextDirection(wml.ST_TextDirectionBtLr)
+			})
+			r.AddCell(func(c *CellBuilder) { c.SetText("b") })
+		})
+	})
+	d.Build()
+
+	x := table.rows[0].cells[0].cell.X().TcPr
+	if x == nil {
+		t.Fatal("no cell properties written")
+	}
+	if x.VAlign == nil || x.VAlign.ValAttr != wml.ST_VerticalJcCenter {
+		t.Errorf("got vertical alignment %+v, want center", x.VAlign)
+	}
+	if x.TextDirection == nil || x.TextDirection.ValAttr != wml.ST_TextDirectionBtLr {
+		t.Errorf("got text direction %+v, want btLr", x.TextDirection)
+	}
+	if x.TcMar == nil {
+		t.Fatal("no cell margins written")
+	}
+	margins := []struct {
+		side   string
+		margin *wml.CT_TblWidth
+		twips  int64
+	}{
+		{"top", x.TcMar.Top, 20},
+		{"right", x.TcMar.Right, 40},
+		{"bottom", x.TcMar.Bottom, 60},
+		{"left", x.TcMar.Left, 80},
+	}
+	for _, m := range margins {
+		if m.margin == nil || m.margin.TypeAttr != wml.ST_TblWidthDxa || m.margin.WAttr == nil ||
+			m.margin.WAttr.ST_DecimalNumberOrPercent == nil || m.margin.WAttr.ST_DecimalNumberOrPercent.ST_UnqualifiedPercentage == nil {
+			t.Errorf("no %s margin written in twips: %+v", m.side, m.margin)
+			continue
+		}
+		if got := *m.margin.WAttr.ST_DecimalNumberOrPercent.ST_UnqualifiedPercentage; got != m.twips {
+			t.Errorf("got %s margin %d, want %d", m.side, got, m.twips)
+		}
+	}
+
+	// the properties not set are not written
+	if x := table.rows[0].cells[1].cell.X().TcPr; x != nil && (x.VAlign != nil || x.TcMar != nil || x.TextDirection != nil) {
+		t.Errorf("got unexpected properties %+v", x)
+	}
+}
@@ -0,0 +1,114 @@
+package docx
+
+import (
+	"sort"
+
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+type RowBuilder struct {
+	config     *Configuration
+	doc        *document.Document
+	row        *document.Row
+	cells      []*CellBuilder
+	table      *TableBuilder // nil if the row is not added by the table builder
+	gridCol    int           // grid column of the next cell
+	header     bool
+	cantSplit  bool
+	height     measurement.Distance
+	heightRule wml.ST_HeightRule
+}
+
+func NewRowBuilder(cfg *Configuration, d *document.Document, r document.Row) *RowBuilder {
+	return &RowBuilder{config: cfg, doc: d, row: &r}
+}
+
+func (r *RowBuilder) AddCell(nextBuilder func(*CellBuilder)) *RowBuilder {
+	r.mergeCells(false)
+	c := newCellBuilder(r.config, r.doc, r.row.AddCell())
+	c.gridCol = r.gridCol
+	r.cells = append(r.cells, c)
+	nextBuilder(c)
+	r.gridCol += c.span()
+	if c.rowspan > 1 && r.table != nil {
+		if r.table.rowspans == nil {
+			r.table.rowspans = map[int]*rowspan{}
+		}
+		r.table.rowspans[c.gridCol] = &rowspan{origin: c, rows: c.rowspan - 1}
+	}
+	return r
+}
+
+// SetCantSplit keeps the row on one page
+func (r *RowBuilder) SetCantSplit() *RowBuilder {
+	r.cantSplit = true
+	return r
+}
+
+// SetHeight sets the height of the row, e.g. wml.ST_HeightRuleExact for the fixed height
+func (r *RowBuilder) SetHeight(height measurement.Distance, rule wml.ST_HeightRule) *RowBuilder {
+	r.height = height
+	r.heightRule = rule
+	return r
+}
+
+// mergeCells adds the cells continuing the merged cells of the rows above at the next grid column, or
+// all the remaining ones at the end of the row with the empty cells of the gaps
+func (r *RowBuilder) mergeCells(all bool) {
+	if r.table == nil {
+		return
+	}
+	for {
+		col := r.gridCol
+		if all {
+			cols := []int{}
+			for k := range r.table.rowspans {
+				if k >= r.gridCol {
+					cols = append(cols, k)
+				}
+			}
+			if len(cols) == 0 {
+				return
+			}
+			sort.Ints(cols)
+			col = cols[0]
+		}
+		span, ok := r.table.rowspans[col]
+		if !ok {
+			return
+		}
+		if col > r.gridCol {
+			// empty cell of the columns before the merged cell at the end of the row, to keep it in its column
+			filler := newCellBuilder(r.config, r.doc, r.row.AddCell())
+			filler.gridCol = r.gridCol
+			filler.colspan = col - r.gridCol
+			r.cells = append(r.cells, filler)
+		}
+		c := newCellBuilder(r.config, r.doc, r.row.AddCell())
+		c.gridCol = col
+		c.merged = span.origin
+		r.cells = append(r.cells, c)
+		r.gridCol = col + span.origin.span()
+		if span.rows--; span.rows == 0 {
+			delete(r.table.rowspans, col)
+		}
+	}
+}
+
+func (r *RowBuilder) Build() {
+	props := r.row.Properties().X()
+	if r.header {
+		props.TblHeader = []*wml.CT_OnOff{wml.NewCT_OnOff()}
+	}
+	if r.cantSplit {
+		props.CantSplit = []*wml.CT_OnOff{wml.NewCT_OnOff()}
+	}
+	if r.heightRule != wml.ST_HeightRuleUnset {
+		r.row.Properties().SetHeight(r.height, r.heightRule)
+	}
+	for _, builder := range r.cells {
+		builder.Build()
+	}
+}
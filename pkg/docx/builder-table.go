@@ -0,0 +1,204 @@
+package docx
+
+import (
+	"strconv"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/schema/soo/ofc/sharedTypes"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+// defaultTextWidth is the width of the text of the A4 page with 2.54cm margins in twips, used if the
+// document doesn't define the page
+const defaultTextWidth = 9026
+
+// twips of the units of the universal measure, e.g. 2.54cm
+var universalUnits = map[string]float64{"mm": 1440 / 25.4, "cm": 1440 / 2.54, "in": 1440, "pt": 20, "pc": 240, "pi": 240}
+
+type TableBuilder struct {
+	config          *Configuration
+	document        *document.Document
+	table           *document.Table
+	rows            []*RowBuilder
+	cellSpacingAuto bool
+	widthPercent    float64
+	borders         *Borders
+	headerRows      int
+	cantSplit       bool
+	columnWidths    []float64
+	rowspans        map[int]*rowspan // merged cells continuing in the next rows by the grid column
+}
+
+// rowspan is the cell merged with the cells below it
+type rowspan struct {
+	origin *CellBuilder
+	rows   int // rows left to merge
+}
+
+func newTableBuilder(cfg *Configuration, doc *document.Document, t document.Table) *TableBuilder {
+	return &TableBuilder{config: cfg, document: doc, table: &t}
+}
+
+func (t *TableBuilder) AddRow(nextBuilder func(*RowBuilder)) *TableBuilder {
+	r := NewRowBuilder(t.config, t.document, t.table.AddRow())
+	r.table = t
+	t.rows = append(t.rows, r)
+	nextBuilder(r)
+	r.mergeCells(true)
+	return t
+}
+
+// SetHeaderRows repeats the first n rows at the top of each page
+func (t *TableBuilder) SetHeaderRows(n int) *TableBuilder {
+	t.headerRows = n
+	return t
+}
+
+// SetCantSplit keeps each row on one page
+func (t *TableBuilder) SetCantSplit() *TableBuilder {
+	t.cantSplit = true
+	return t
+}
+
+// SetColumnWidths defines the grid of the columns in percent of the table width, the cells without
+// width follow the grid and the layout of the table is fixed
+func (t *TableBuilder) SetColumnWidths(widths ...float64) *TableBuilder {
+	t.columnWidths = widths
+	return t
+}
+
+func (t *TableBuilder) SetCellSpacingAuto() *TableBuilder {
+	t.cellSpacingAuto = true
+	return t
+}
+
+func (t *TableBuilder) SetWidthPercent(value float64) *TableBuilder {
+	t.widthPercent = value
+	return t
+}
+
+func (t *TableBuilder) SetBorders(callback func(*Borders)) *TableBuilder {
+	t.borders = &Borders{}
+	callback(t.borders)
+	return t
+}
+
+func (t *TableBuilder) Build() {
+	if t.borders != nil {
+		b := t.table.Properties().Borders()
+		if t.borders.Top != nil {
+			b.SetTop(t.borders.Top.Style, t.borders.Top.Color, t.borders.Top.Thickness)
+		}
+		if t.borders.Right != nil {
+			b.SetRight(t.borders.Right.Style, t.borders.Right.Color, t.borders.Right.Thickness)
+		}
+		if t.borders.Bottom != nil {
+			b.SetBottom(t.borders.Bottom.Style, t.borders.Bottom.Color, t.borders.Bottom.Thickness)
+		}
+		if t.borders.Left != nil {
+			b.SetLeft(t.borders.Left.Style, t.borders.Left.Color, t.borders.Left.Thickness)
+		}
+		if t.borders.InsideHorizontal != nil {
+			b.SetInsideHorizontal(t.borders.InsideHorizontal.Style, t.borders.InsideHorizontal.Color, t.borders.InsideHorizontal.Thickness)
+		}
+		if t.borders.InsideVertical != nil {
+			b.SetInsideVertical(t.borders.InsideVertical.Style, t.borders.InsideVertical.Color, t.borders.InsideVertical.Thickness)
+		}
+	}
+
+	if t.widthPercent > 0 {
+		t.table.Properties().SetWidthPercent(t.widthPercent)
+	}
+	if t.cellSpacingAuto {
+		t.table.Properties().SetCellSpacingAuto()
+	}
+
+	if len(t.columnWidths) > 0 {
+		t.buildGrid()
+	}
+
+	for i, row := range t.rows {
+		if i < t.headerRows {
+			row.header = true
+		}
+		if t.cantSplit {
+			row.SetCantSplit()
+		}
+		row.Build()
+	}
+}
+
+// buildGrid sets the column grid and the width of the cells by the grid columns they span
+func (t *TableBuilder) buildGrid() {
+	width := textWidth(t.document)
+	if t.widthPercent > 0 {
+		width = width * t.widthPercent / 100
+	}
+	tbl := t.table.X()
+	if tbl.TblGrid == nil {
+		tbl.TblGrid = wml.NewCT_TblGrid()
+	}
+	tbl.TblGrid.GridCol = nil
+	for _, w := range t.columnWidths {
+		col := wml.NewCT_TblGridCol()
+		col.WAttr = &sharedTypes.ST_TwipsMeasure{ST_UnsignedDecimalNumber: gooxml.Uint64(uint64(width * w / 100))}
+		tbl.TblGrid.GridCol = append(tbl.TblGrid.GridCol, col)
+	}
+	t.table.Properties().SetLayout(wml.ST_TblLayoutTypeFixed)
+
+	for _, row := range t.rows {
+		for _, c := range row.cells {
+			if c.widthPercent > 0 || c.merged != nil {
+				continue
+			}
+			for i := c.gridCol; i < c.gridCol+c.span() && i < len(t.columnWidths); i++ {
+				c.widthPercent += t.columnWidths[i]
+			}
+		}
+	}
+}
+
+// textWidth returns the width of the text in twips, the page width less the left and right margins of the
+// body section, the column grid is relative to it
+func textWidth(doc *document.Document) float64 {
+	if doc == nil || doc.X() == nil {
+		return defaultTextWidth
+	}
+	body := doc.X().Body
+	if body == nil || body.SectPr == nil || body.SectPr.EG_SectPrContents == nil || body.SectPr.EG_SectPrContents.PgSz == nil {
+		return defaultTextWidth
+	}
+	page := body.SectPr.EG_SectPrContents
+	width, ok := twips(page.PgSz.WAttr)
+	if !ok {
+		return defaultTextWidth
+	}
+	if page.PgMar != nil {
+		left, _ := twips(&page.PgMar.LeftAttr)
+		right, _ := twips(&page.PgMar.RightAttr)
+		width -= left + right
+	}
+	if width <= 0 {
+		return defaultTextWidth
+	}
+	return width
+}
+
+// twips returns the measure in twips, ok is false if it is not set or invalid
+func twips(m *sharedTypes.ST_TwipsMeasure) (float64, bool) {
+	switch {
+	case m == nil:
+		return 0, false
+	case m.ST_UnsignedDecimalNumber != nil:
+		return float64(*m.ST_UnsignedDecimalNumber), true
+	case m.ST_PositiveUniversalMeasure != nil && len(*m.ST_PositiveUniversalMeasure) > 2:
+		s := *m.ST_PositiveUniversalMeasure
+		unit, ok := universalUnits[s[len(s)-2:]]
+		v, err := strconv.ParseFloat(s[:len(s)-2], 64)
+		if ok && err == nil {
+			return v * unit, true
+		}
+	}
+	return 0, false
+}
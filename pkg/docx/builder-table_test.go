@@ -0,0 +1,70 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/schema/soo/ofc/sharedTypes"
+)
+
+func TestMergeCellsPadsGap(t *testing.T) {
+	d, err := NewDocumentBuilder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var table *TableBuilder
+	d.AddTable(func(tb *TableBuilder) {
+		table = tb
+		tb.AddRow(func(r *RowBuilder) {
+			r.AddCell(func(c *CellBuilder) { c.SetText("a") })
+			r.AddCell(func(c *CellBuilder) { c.SetText("b") })
+			r.AddCell(func(c *CellBuilder) { c.SetText("c").SetRowspan(3) })
+		})
+		tb.AddRow(func(r *RowBuilder) {
+			r.AddCell(func(c *CellBuilder) { c.SetText("d") })
+		})
+		tb.AddRow(func(r *RowBuilder) {})
+	})
+
+	type layout struct {
+		gridCol, span int
+		merged        bool
+	}
+	want := [][]layout{
+		{{0, 1, false}, {1, 1, false}, {2, 1, false}},
+		{{0, 1, false}, {1, 1, false}, {2, 1, true}},
+		{{0, 2, false}, {2, 1, true}},
+	}
+	for i, row := range table.rows {
+		got := []layout{}
+		for _, c := range row.cells {
+			got = append(got, layout{c.gridCol, c.span(), c.merged != nil})
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("row %d: got %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestTwips(t *testing.T) {
+	tests := []struct {
+		measure *sharedTypes.ST_TwipsMeasure
+		want    float64
+		ok      bool
+	}{
+		{nil, 0, false},
+		{&sharedTypes.ST_TwipsMeasure{ST_UnsignedDecimalNumber: gooxml.Uint64(11906)}, 11906, true},
+		{&sharedTypes.ST_TwipsMeasure{ST_PositiveUniversalMeasure: gooxml.String("1in")}, 1440, true},
+		{&sharedTypes.ST_TwipsMeasure{ST_PositiveUniversalMeasure: gooxml.String("2.54cm")}, 1440, true},
+		{&sharedTypes.ST_TwipsMeasure{ST_PositiveUniversalMeasure: gooxml.String("12pt")}, 240, true},
+		{&sharedTypes.ST_TwipsMeasure{ST_PositiveUniversalMeasure: gooxml.String("1ft")}, 0, false},
+		{&sharedTypes.ST_TwipsMeasure{}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := twips(tt.measure)
+		if ok != tt.ok || got < tt.want-0.01 || got > tt.want+0.01 {
+			t.Errorf("twips(%+v) = %v, %v, want %v, %v", tt.measure, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Package docx is the fluent builder of the Word (.docx) document used by pst, it can be used for other
+reports as well. The builders only record the settings, the document is changed by Build. The borders,
+colors and sizes are the types of gooxml, see ExampleNewDocumentBuilder.
+
+	import (
+		"baliance.com/gooxml/color"
+		"baliance.com/gooxml/measurement"
+		"baliance.com/gooxml/schema/soo/wml"
+		"github.com/zrs01/pst/pkg/docx"
+	)
+
+	d, err := docx.NewDocumentBuilder("template.docx", docx.Configuration{FontFamily: "Arial", FontSize: 10})
+	if err != nil {
+		return err
+	}
+	d.AddParagraph(func(p *docx.ParagraphBuilder) {
+		p.SetStyle("Heading1").SetText("Report")
+	}).AddTable(func(t *docx.TableBuilder) {
+		t.SetWidthPercent(100).SetBorders(func(b *docx.Borders) {
+			b.SetBorderAll(wml.ST_BorderSingle, color.Auto, measurement.Distance(0.5*measurement.Point))
+		})
+		t.AddRow(func(r *docx.RowBuilder) {
+			r.AddCell(func(c *docx.CellBuilder) { c.SetBold().SetWidthPercent(20).SetText("Name") })
+			r.AddCell(func(c *docx.CellBuilder) { c.SetText([]string{"line 1", "line 2"}) })
+		})
+	})
+	d.Build()
+	return d.Document.SaveToFile("report.docx")
+
+The package follows the semantic versioning of the pst releases, the exported API is not changed
+incompatibly within the same major version.
+*/
+package docx
@@ -0,0 +1,40 @@
+package docx_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+	"github.com/zrs01/pst/pkg/docx"
+)
+
+func ExampleNewDocumentBuilder() {
+	dir, err := os.MkdirTemp("", "docx")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// blank template is the default document of gooxml
+	d, err := docx.NewDocumentBuilder("", docx.Configuration{FontFamily: "Arial", FontSize: 10})
+	if err != nil {
+		panic(err)
+	}
+	d.AddParagraph(func(p *docx.ParagraphBuilder) {
+		p.SetStyle("Heading1").SetText("Report")
+	}).AddTable(func(t *docx.TableBuilder) {
+		t.SetWidthPercent(100).SetBorders(func(b *docx.Borders) {
+			b.SetBorderAll(wml.ST_BorderSingle, color.Auto, measurement.Distance(0.5*measurement.Point))
+		})
+		t.AddRow(func(r *docx.RowBuilder) {
+			r.AddCell(func(c *docx.CellBuilder) { c.SetBold().SetWidthPercent(20).SetText("Name") })
+			r.AddCell(func(c *docx.CellBuilder) { c.SetText([]string{"line 1", "line 2"}) })
+		})
+	})
+	d.Build()
+	fmt.Println(d.Document.SaveToFile(filepath.Join(dir, "report.docx")))
+	// Output: <nil>
+}
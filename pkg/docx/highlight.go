@@ -0,0 +1,202 @@
+package docx
+
+import (
+	"strings"
+
+	"baliance.com/gooxml/color"
+)
+
+// tokenKind is the kind of the code token, it decides the color of the run
+type tokenKind int
+
+const (
+	tokenPlain tokenKind = iota
+	tokenKeyword
+	tokenString
+	tokenComment
+	tokenNumber
+)
+
+// colors of the token kinds, the same as the light theme of VS Code
+var tokenColors = map[tokenKind]color.Color{
+	tokenKeyword: color.FromHex("0000FF"),
+	tokenString:  color.FromHex("A31515"),
+	tokenComment: color.FromHex("008000"),
+	tokenNumber:  color.FromHex("098658"),
+}
+
+// color of the line numbers of the code block
+var lineNumberColor = color.FromHex("808080")
+
+type codeToken struct {
+	text string
+	kind tokenKind
+}
+
+// codeLanguage is the syntax of the language for the highlighting
+type codeLanguage struct {
+	keywords     map[string]bool
+	ignoreCase   bool // keywords are case insensitive, e.g. SQL
+	lineComments []string
+	blockComment [2]string
+	quotes       string // delimiters of the strings
+	escape       bool   // backslash escapes the next character in the strings
+}
+
+func keywords(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, word := range strings.Fields(s) {
+		m[word] = true
+	}
+	return m
+}
+
+var (
+	sqlLanguage = &codeLanguage{
+		keywords: keywords(`select from where insert into values update set delete create replace table view index drop alter
+			add column primary key foreign references not null and or in is like between exists join inner left right
+			outer full cross on as order by group having distinct union all case when then else end begin commit
+			rollback declare procedure function return returns if while loop for cursor open fetch close exec execute
+			with limit offset top grant revoke trigger default constraint unique check cascade asc desc varchar
+			varchar2 char int integer number date timestamp decimal numeric true false`),
+		ignoreCase:   true,
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "'",
+	}
+	javaLanguage = &codeLanguage{
+		keywords: keywords(`abstract assert boolean break byte case catch char class const continue default do double else
+			enum extends final finally float for goto if implements import instanceof int interface long native new
+			package private protected public return short static strictfp super switch synchronized this throw throws
+			transient try void volatile while true false null var record`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+		escape:       true,
+	}
+	javascriptLanguage = &codeLanguage{
+		keywords: keywords(`break case catch class const continue debugger default delete do else export extends finally
+			for function if import in instanceof new return super switch this throw try typeof var void while with
+			yield let static await async of true false null undefined`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		escape:       true,
+	}
+	goLanguage = &codeLanguage{
+		keywords: keywords(`break case chan const continue default defer else fallthrough for func go goto if import
+			interface map package range return select struct switch type var true false nil iota`),
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+		escape:       true,
+	}
+)
+
+// languages of the highlighting by name, case insensitive
+var codeLanguages = map[string]*codeLanguage{
+	"sql":        sqlLanguage,
+	"plsql":      sqlLanguage,
+	"java":       javaLanguage,
+	"javascript": javascriptLanguage,
+	"js":         javascriptLanguage,
+	"typescript": javascriptLanguage,
+	"ts":         javascriptLanguage,
+	"go":         goLanguage,
+	"golang":     goLanguage,
+}
+
+// highlight splits the code into the lines of tokens, the code of unknown language is plain text
+func highlight(code, lang string) [][]codeToken {
+	lines := [][]codeToken{{}}
+	add := func(text string, kind tokenKind) {
+		for i, part := range strings.Split(text, "\n") {
+			if i > 0 {
+				lines = append(lines, []codeToken{})
+			}
+			if part == "" {
+				continue
+			}
+			line := lines[len(lines)-1]
+			if n := len(line); n > 0 && line[n-1].kind == kind {
+				line[n-1].text += part
+			} else {
+				lines[len(lines)-1] = append(line, codeToken{text: part, kind: kind})
+			}
+		}
+	}
+
+	language := codeLanguages[strings.ToLower(strings.TrimSpace(lang))]
+	if language == nil {
+		add(code, tokenPlain)
+		return lines
+	}
+	for i := 0; i < len(code); {
+		n, kind := language.next(code[i:])
+		add(code[i:i+n], kind)
+		i += n
+	}
+	return lines
+}
+
+// next returns the length and kind of the token at the beginning of the code
+func (l *codeLanguage) next(code string) (int, tokenKind) {
+	for _, prefix := range l.lineComments {
+		if strings.HasPrefix(code, prefix) {
+			if end := strings.IndexByte(code, '\n'); end >= 0 {
+				return end, tokenComment
+			}
+			return len(code), tokenComment
+		}
+	}
+	if l.blockComment[0] != "" && strings.HasPrefix(code, l.blockComment[0]) {
+		if end := strings.Index(code[len(l.blockComment[0]):], l.blockComment[1]); end >= 0 {
+			return len(l.blockComment[0]) + end + len(l.blockComment[1]), tokenComment
+		}
+		return len(code), tokenComment
+	}
+
+	c := code[0]
+	switch {
+	case strings.IndexByte(l.quotes, c) >= 0:
+		for i := 1; i < len(code); i++ {
+			switch {
+			case l.escape && code[i] == '\\' && c != '`':
+				i++
+			case code[i] == c && !l.escape && i+1 < len(code) && code[i+1] == c:
+				// '' in SQL
+				i++
+			case code[i] == c:
+				return i + 1, tokenString
+			case code[i] == '\n' && c != '`':
+				// unterminated string
+				return i, tokenString
+			}
+		}
+		return len(code), tokenString
+	case c >= '0' && c <= '9':
+		i := 1
+		for i < len(code) && (isWordChar(code[i]) || code[i] == '.') {
+			i++
+		}
+		return i, tokenNumber
+	case isWordChar(c):
+		i := 1
+		for i < len(code) && isWordChar(code[i]) {
+			i++
+		}
+		word := code[:i]
+		if l.ignoreCase {
+			word = strings.ToLower(word)
+		}
+		if l.keywords[word] {
+			return i, tokenKeyword
+		}
+		return i, tokenPlain
+	}
+	return 1, tokenPlain
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	plain := func(s string) codeToken { return codeToken{s, tokenPlain} }
+	keyword := func(s string) codeToken { return codeToken{s, tokenKeyword} }
+	str := func(s string) codeToken { return codeToken{s, tokenString} }
+	comment := func(s string) codeToken { return codeToken{s, tokenComment} }
+	number := func(s string) codeToken { return codeToken{s, tokenNumber} }
+
+	tests := []struct {
+		name string
+		code string
+		lang string
+		want [][]codeToken
+	}{
+		{"unknown language", "select 1", "cobol", [][]codeToken{{plain("select 1")}}},
+		{"empty", "", "go", [][]codeToken{{}}},
+		{
+			name: "sql case insensitive",
+			code: "SELECT name FROM t WHERE id = 10 -- by id",
+			lang: " SQL ",
+			want: [][]codeToken{{
+				keyword("SELECT"), plain(" name "), keyword("FROM"), plain(" t "), keyword("WHERE"), plain(" id = "),
+				number("10"), plain(" "), comment("-- by id"),
+			}},
+		},
+		{
+			name: "sql quotes",
+			code: "'it''s' \"x\"",
+			lang: "plsql",
+			want: [][]codeToken{{str("'it''s'"), plain(" \"x\"")}},
+		},
+		{
+			name: "go lines",
+			code: "func f() {\n\treturn \"a\\\"b\" // done\n}",
+			lang: "go",
+			want: [][]codeToken{
+				{keyword("func"), plain(" f() {")},
+				{plain("\t"), keyword("return"), plain(" "), str(`"a\"b"`), plain(" "), comment("// done")},
+				{plain("}")},
+			},
+		},
+		{
+			name: "block comment across lines",
+			code: "/* a\nb */ x",
+			lang: "java",
+			want: [][]codeToken{{comment("/* a")}, {comment("b */"), plain(" x")}},
+		},
+		{
+			name: "unterminated string stops at the line",
+			code: "var s = 'abc\nlet",
+			lang: "js",
+			want: [][]codeToken{{keyword("var"), plain(" s = "), str("'abc")}, {keyword("let")}},
+		},
+		{
+			name: "template string across lines",
+			code: "`a\nb` 1.5e3",
+			lang: "ts",
+			want: [][]codeToken{{str("`a")}, {str("b`"), plain(" "), number("1.5e3")}},
+		},
+		{
+			name: "keywords are words",
+			code: "format iffy $if",
+			lang: "golang",
+			want: [][]codeToken{{plain("format iffy $if")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.code, tt.lang); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package docx
+
+import (
+	"fmt"
+	"strings"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/document"
+)
+
+// characters which can be escaped by backslash to keep the marker as text, e.g. \*
+const inlineEscapes = "*~`[]\\"
+
+// inlineStyle is the Markdown-style formatting of the text
+type inlineStyle struct {
+	bold   bool   // **bold**
+	italic bool   // *italic*
+	code   bool   // `code`
+	strike bool   // ~~strike~~
+	link   string // [text](url)
+}
+
+// inlineSpan is a part of the text with the same formatting
+type inlineSpan struct {
+	text  string
+	style inlineStyle
+}
+
+// parseInline splits the text into the spans of the inline formatting, the markers without the closing
+// one or enclosing spaces are kept as text, e.g. 5 * 3 or *.csv
+func parseInline(text string) []inlineSpan {
+	spans := []inlineSpan{}
+	parseSpans(text, inlineStyle{}, &spans)
+	if len(spans) == 0 {
+		// the run of the blank text is still needed, e.g. for the line break
+		spans = append(spans, inlineSpan{})
+	}
+	return spans
+}
+
+func parseSpans(text string, style inlineStyle, spans *[]inlineSpan) {
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			*spans = append(*spans, inlineSpan{text: plain.String(), style: style})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(inlineEscapes, text[i+1]) >= 0:
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end > 0 {
+				flush()
+				s := style
+				s.code = true
+				*spans = append(*spans, inlineSpan{text: text[i+1 : i+1+end], style: s})
+				i += end + 2
+				continue
+			}
+		case c == '[':
+			if label, url, n := parseLink(text[i:]); n > 0 {
+				flush()
+				s := style
+				s.link = url
+				parseSpans(label, s, spans)
+				i += n
+				continue
+			}
+		case strings.HasPrefix(text[i:], "**"), strings.HasPrefix(text[i:], "~~"), c == '*':
+			markers := []string{text[i : i+1]}
+			if strings.HasPrefix(text[i:], "***") {
+				// italic first for ***bold italic*** or ***bold** italic*, then bold for ***italic* bold**
+				markers = []string{"*", "**"}
+			} else if strings.HasPrefix(text[i:], "**") || strings.HasPrefix(text[i:], "~~") {
+				markers = []string{text[i : i+2]}
+			}
+			matched := false
+			for _, marker := range markers {
+				end := findCloser(text, i+len(marker), marker)
+				if end < 0 {
+					continue
+				}
+				flush()
+				s := style
+				switch marker {
+				case "**":
+					s.bold = true
+				case "~~":
+					s.strike = true
+				default:
+					s.italic = true
+				}
+				parseSpans(text[i+len(marker):end], s, spans)
+				i = end + len(marker)
+				matched = true
+				break
+			}
+			if matched {
+				continue
+			}
+			if marker := markers[len(markers)-1]; marker != "*" {
+				// e.g. ** as text, the single * is not tried again
+				plain.WriteString(marker)
+				i += len(marker)
+				continue
+			}
+		}
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+// findCloser returns the index of the closing marker of the text starting at the index, -1 if not found.
+// The text must not start or end with space, the code spans are skipped.
+func findCloser(text string, start int, marker string) int {
+	if start >= len(text) || text[start] == ' ' || text[start] == '\t' {
+		return -1
+	}
+	for i := start; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end >= 0 {
+				i += end + 1
+			}
+		case strings.HasPrefix(text[i:], marker):
+			if marker == "*" && strings.HasPrefix(text[i:], "**") {
+				// bold inside italic
+				if end := findCloser(text, i+2, "**"); end > 0 {
+					i = end + 1
+					continue
+				}
+			}
+			// the text before the run of the markers, e.g. the run *** closes both *a **b***
+			j := i
+			for j > start && text[j-1] == marker[0] {
+				j--
+			}
+			if j > start && text[j-1] != ' ' && text[j-1] != '\t' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseLink parses [label](url) at the beginning of the text, returns the length or 0 if it is not a link
+func parseLink(text string) (string, string, int) {
+	mid := strings.Index(text, "](")
+	if mid < 2 {
+		return "", "", 0
+	}
+	end := strings.IndexByte(text[mid+2:], ')')
+	if end < 1 {
+		return "", "", 0
+	}
+	url := text[mid+2 : mid+2+end]
+	if strings.ContainsAny(url, " \t") || strings.ContainsRune(text[1:mid], '[') {
+		return "", "", 0
+	}
+	return text[1:mid], url, mid + 3 + end
+}
+
+// formattedRun is the run added for the span, the text is written by the caller after the break if any
+type formattedRun struct {
+	run    document.Run
+	text   string
+	field  string // code of the field instead of the text
+	style  inlineStyle
+	linked bool // the run of the hyperlink
+}
+
+func (r formattedRun) write() {
+	if r.field != "" {
+		r.run.AddFieldWithFormatting(r.field, "", true)
+		return
+	}
+	r.run.AddText(r.text)
+}
+
+// addInlineRuns adds the runs of the text with the inline formatting to the paragraph, the words having
+// bookmark are linked to the heading, and [text](url) and the bare urls to the external target
+func addInlineRuns(cfg *Configuration, p document.Paragraph, text string, bookmarks map[string]string) []formattedRun {
+	runs := []formattedRun{}
+	for _, span := range parseInline(text) {
+		spans := []inlineSpan{span}
+		if span.style.link == "" && !span.style.code {
+			spans = []inlineSpan{}
+			for _, s := range urlSegments(span.text) {
+				style := span.style
+				style.link = s.style.link
+				spans = append(spans, inlineSpan{text: s.text, style: style})
+			}
+		}
+		for _, span := range spans {
+			segments := []textSegment{{text: span.text}}
+			if span.style.link == "" && !span.style.code {
+				segments = linkSegments(span.text, bookmarks)
+			}
+			for _, segment := range segments {
+				var run document.Run
+				switch {
+				case span.style.link != "":
+					link := p.AddHyperLink()
+					link.SetTarget(span.style.link)
+					run = link.AddRun()
+				case segment.bookmark != "":
+					link := p.AddHyperLink()
+					link.X().AnchorAttr = gooxml.String(segment.bookmark)
+					run = link.AddRun()
+				default:
+					run = p.AddRun()
+				}
+				if span.style.link != "" || segment.bookmark != "" {
+					setLinkStyle(run)
+				}
+				if span.style.bold {
+					run.Properties().SetBold(true)
+				}
+				if span.style.italic {
+					run.Properties().SetItalic(true)
+				}
+				if span.style.strike {
+					run.Properties().SetStrikeThrough(true)
+				}
+				if span.style.code {
+					run.Properties().SetFontFamily(cfg.CodeFontFamily)
+				}
+				runs = append(runs, formattedRun{run: run, text: segment.text, style: span.style, linked: span.style.link != "" || segment.bookmark != ""})
+
+				if segment.bookmark != "" && cfg.PageRefs {
+					// e.g. UF011A (page 12)
+					runs = append(runs,
+						formattedRun{run: p.AddRun(), text: " (page ", style: span.style},
+						formattedRun{run: p.AddRun(), field: fmt.Sprintf(`%s %s \h`, RefPage, segment.bookmark), style: span.style},
+						formattedRun{run: p.AddRun(), text: ")", style: span.style},
+					)
+				}
+			}
+		}
+	}
+	return runs
+}
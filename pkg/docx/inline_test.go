@@ -0,0 +1,47 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInline(t *testing.T) {
+	bold := inlineStyle{bold: true}
+	italic := inlineStyle{italic: true}
+	both := inlineStyle{bold: true, italic: true}
+	code := inlineStyle{code: true}
+
+	tests := []struct {
+		text string
+		want []inlineSpan
+	}{
+		{"", []inlineSpan{{}}},
+		{"plain text", []inlineSpan{{"plain text", inlineStyle{}}}},
+		{"5 * 3 = 15", []inlineSpan{{"5 * 3 = 15", inlineStyle{}}}},
+		{"*.csv and *.xlsx", []inlineSpan{{"*.csv and *.xlsx", inlineStyle{}}}},
+		{"a ** b", []inlineSpan{{"a ** b", inlineStyle{}}}},
+		{"**bold** and *italic*", []inlineSpan{{"bold", bold}, {" and ", inlineStyle{}}, {"italic", italic}}},
+		{"~~old~~ new", []inlineSpan{{"old", inlineStyle{strike: true}}, {" new", inlineStyle{}}}},
+		{"*a **b** c*", []inlineSpan{{"a ", italic}, {"b", both}, {" c", italic}}},
+		{"**a *b* c**", []inlineSpan{{"a ", bold}, {"b", both}, {" c", bold}}},
+		{"***bold italic***", []inlineSpan{{"bold italic", both}}},
+		{"***a** b*", []inlineSpan{{"a", both}, {" b", italic}}},
+		{"***a* b**", []inlineSpan{{"a", both}, {" b", bold}}},
+		{"*a **b***", []inlineSpan{{"a ", italic}, {"b", both}}},
+		{"*** not ***", []inlineSpan{{"*** not ***", inlineStyle{}}}},
+		{`\*not italic\* \\ \x`, []inlineSpan{{`*not italic* \ \x`, inlineStyle{}}}},
+		{"`a*b*c` *d*", []inlineSpan{{"a*b*c", code}, {" ", inlineStyle{}}, {"d", italic}}},
+		{"*x `*` y*", []inlineSpan{{"x ", italic}, {"*", inlineStyle{italic: true, code: true}}, {" y", italic}}},
+		{"`open", []inlineSpan{{"`open", inlineStyle{}}}},
+		{"see [site](https://example.com).", []inlineSpan{{"see ", inlineStyle{}}, {"site", inlineStyle{link: "https://example.com"}}, {".", inlineStyle{}}}},
+		{"[a *b*](u)", []inlineSpan{{"a ", inlineStyle{link: "u"}}, {"b", inlineStyle{italic: true, link: "u"}}}},
+		{"[no link] (u) [x](a b)", []inlineSpan{{"[no link] (u) [x](a b)", inlineStyle{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := parseInline(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
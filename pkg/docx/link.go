@@ -0,0 +1,35 @@
+package docx
+
+import "regexp"
+
+// word which could be linked to the bookmark, e.g. UF011A or PG-GEN-002
+var linkToken = regexp.MustCompile(`[A-Za-z0-9](?:[A-Za-z0-9_-]*[A-Za-z0-9])?`)
+
+// textSegment is a part of the text, linked to the bookmark if any
+type textSegment struct {
+	text     string
+	bookmark string
+}
+
+// linkSegments splits the text into the plain parts and the words having bookmark
+func linkSegments(text string, bookmarks map[string]string) []textSegment {
+	segments := []textSegment{}
+	last := 0
+	if len(bookmarks) > 0 {
+		for _, loc := range linkToken.FindAllStringIndex(text, -1) {
+			bookmark, ok := bookmarks[text[loc[0]:loc[1]]]
+			if !ok {
+				continue
+			}
+			if loc[0] > last {
+				segments = append(segments, textSegment{text: text[last:loc[0]]})
+			}
+			segments = append(segments, textSegment{text: text[loc[0]:loc[1]], bookmark: bookmark})
+			last = loc[1]
+		}
+	}
+	if last < len(text) || len(segments) == 0 {
+		segments = append(segments, textSegment{text: text[last:]})
+	}
+	return segments
+}
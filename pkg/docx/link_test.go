@@ -0,0 +1,131 @@
+package docx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+func TestLinkSegments(t *testing.T) {
+	bookmarks := map[string]string{"UF011A": "F_UF011A", "PG-GEN-002": "S_PG_GEN_002"}
+	tests := []struct {
+		text      string
+		bookmarks map[string]string
+		want      []textSegment
+	}{
+		{"", bookmarks, []textSegment{{}}},
+		{"see UF011A", nil, []textSegment{{text: "see UF011A"}}},
+		{"see UF011A.", bookmarks, []textSegment{{text: "see "}, {"UF011A", "F_UF011A"}, {text: "."}}},
+		{"UF011A and PG-GEN-002", bookmarks, []textSegment{{"UF011A", "F_UF011A"}, {text: " and "}, {"PG-GEN-002", "S_PG_GEN_002"}}},
+		{"UF011AB, XUF011A, PG-GEN", bookmarks, []textSegment{{text: "UF011AB, XUF011A, PG-GEN"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := linkSegments(tt.text, tt.bookmarks); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParagraphLinks(t *testing.T) {
+	d, err := NewDocumentBuilder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paragraph := d.Document.AddParagraph()
+	p := newParagraphBuilder(d.config, d.Document, paragraph)
+	p.AddBookmark("F_UF011A").
+		SetText("see").
+		AddHyperlink("site", "https://example.com").
+		AddInternalLink("UF011A", "F_UF011A").
+		AddReference("F_UF011A", RefText).
+		AddReference("F_UF011A", RefPage).
+		Build()
+
+	want := []string{
+		"bookmark F_UF011A",
+		"see",
+		"link url site",
+		"link #F_UF011A UF011A",
+		`{REF F_UF011A \h}`,
+		`{PAGEREF F_UF011A \h}`,
+	}
+	if got := paragraphContents(paragraph); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInlineLinks(t *testing.T) {
+	tests := []struct {
+		text     string
+		pageRefs bool
+		want     []string
+	}{
+		{"see UF011A", false, []string{"see ", "link #F_UF011A UF011A"}},
+		{"see UF011A", true, []string{"see ", "link #F_UF011A UF011A", " (page ", `{PAGEREF F_UF011A \h}`, ")"}},
+		{"[UF011A](https://example.com) or https://example.com", false, []string{"link url UF011A", " or ", "link url https://example.com"}},
+		{"`UF011A` **UF011A**", false, []string{"UF011A", " ", "link #F_UF011A UF011A"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			cfg := &Configuration{CodeFontFamily: "Consolas", PageRefs: tt.pageRefs}
+			p := document.New().AddParagraph()
+			for _, r := range addInlineRuns(cfg, p, tt.text, map[string]string{"UF011A": "F_UF011A"}) {
+				r.write()
+			}
+			if got := paragraphContents(p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// paragraphContents describes the bookmarks, hyperlinks, fields and text of the paragraph
+func paragraphContents(p document.Paragraph) []string {
+	contents := []string{}
+	for _, pc := range p.X().EG_PContent {
+		if link := pc.Hyperlink; link != nil {
+			target := "url"
+			if link.AnchorAttr != nil {
+				target = "#" + *link.AnchorAttr
+			}
+			contents = append(contents, "link "+target+" "+runsText(link.EG_ContentRunContent))
+		}
+		for _, rc := range pc.EG_ContentRunContent {
+			for _, rl := range rc.EG_RunLevelElements {
+				for _, rm := range rl.EG_RangeMarkupElements {
+					if rm.BookmarkStart != nil {
+						contents = append(contents, "bookmark "+rm.BookmarkStart.NameAttr)
+					}
+				}
+			}
+		}
+		if text := runsText(pc.EG_ContentRunContent); text != "" {
+			contents = append(contents, text)
+		}
+	}
+	return contents
+}
+
+// runsText returns the text of the runs, the field is in braces
+func runsText(rcs []*wml.EG_ContentRunContent) string {
+	var sb strings.Builder
+	for _, rc := range rcs {
+		if rc.R == nil {
+			continue
+		}
+		for _, ic := range rc.R.EG_RunInnerContent {
+			if ic.T != nil {
+				sb.WriteString(ic.T.Content)
+			}
+			if ic.InstrText != nil {
+				sb.WriteString("{" + ic.InstrText.Content + "}")
+			}
+		}
+	}
+	return sb.String()
+}
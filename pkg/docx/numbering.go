@@ -0,0 +1,167 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+// ListKind is the numbering of the list in the cell
+type ListKind int
+
+const (
+	ListNone    ListKind = iota // paragraph per line, nested items are bulleted
+	ListBullet                  // •, ◦, ▪
+	ListDecimal                 // 1., 1.1., 1.1.1.
+)
+
+// ParseListKind converts none, bullet or decimal to the kind, ok is false if it is unknown
+func ParseListKind(s string) (ListKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none":
+		return ListNone, true
+	case "bullet":
+		return ListBullet, true
+	case "decimal", "number":
+		return ListDecimal, true
+	}
+	return ListNone, false
+}
+
+// levels of the numbering definition supported by Word
+const listLevels = 9
+
+var bulletChars = []string{"•", "◦", "▪"}
+
+// listItem is the line of the cell, the kind is set if the text has the list marker, e.g. - or 1.
+type listItem struct {
+	level int
+	kind  ListKind
+	text  string
+}
+
+// list marker of the line in the multi-line text, e.g. "  - item" or "1. step"
+var listMarker = regexp.MustCompile(`^([ \t]*)([-*•]|\d+[.)])[ \t]+(.*)$`)
+
+// toListItems converts the value to the items, the nested sequences and the indented list markers of the
+// multi-line text are the items of the next level
+func toListItems(v interface{}) []listItem {
+	items := []listItem{}
+	addListItems(v, 0, &items)
+	return items
+}
+
+func addListItems(v interface{}, level int, items *[]listItem) {
+	if level >= listLevels {
+		level = listLevels - 1
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, value := range t {
+			if _, ok := value.([]interface{}); ok {
+				addListItems(value, level+1, items)
+				continue
+			}
+			addListItems(value, level, items)
+		}
+	case string:
+		*items = append(*items, textListItems(strings.TrimSpace(t), level)...)
+	default:
+		for _, s := range ToStrArray(v) {
+			*items = append(*items, listItem{level: level, text: s})
+		}
+	}
+}
+
+// textListItems splits the multi-line text having the list markers into the items, the text without
+// the markers is one item
+func textListItems(text string, level int) []listItem {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	marked := false
+	for _, line := range lines {
+		marked = marked || listMarker.MatchString(line)
+	}
+	if len(lines) == 1 || !marked {
+		return []listItem{{level: level, text: text}}
+	}
+
+	items := []listItem{}
+	indents := []int{} // indents of the open levels
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		m := listMarker.FindStringSubmatch(line)
+		if m == nil {
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			if n := len(items); n > 0 && items[n-1].kind != ListNone && len(indents) > 0 && indent > indents[len(indents)-1] {
+				// continuation of the item, \n is the line break of the cell
+				items[n-1].text += `\n` + strings.TrimSpace(line)
+				continue
+			}
+			items = append(items, listItem{level: level, text: strings.TrimSpace(line)})
+			indents = indents[:0]
+			continue
+		}
+		indent := len(m[1])
+		for len(indents) > 0 && indent < indents[len(indents)-1] {
+			indents = indents[:len(indents)-1]
+		}
+		if len(indents) == 0 || indent > indents[len(indents)-1] {
+			indents = append(indents, indent)
+		}
+		kind := ListBullet
+		if m[2][0] >= '0' && m[2][0] <= '9' {
+			kind = ListDecimal
+		}
+		l := level + len(indents) - 1
+		if l >= listLevels {
+			l = listLevels - 1
+		}
+		items = append(items, listItem{level: l, kind: kind, text: m[3]})
+	}
+	return items
+}
+
+// numbering creates the numbering definitions of the lists in the document
+type numbering struct {
+	bullet *document.NumberingDefinition // shared by all bulleted lists
+}
+
+// definition returns the numbering definition of the kind, the numbered list has its own definition so that
+// it starts from 1
+func (n *numbering) definition(doc *document.Document, kind ListKind) document.NumberingDefinition {
+	if kind == ListBullet && n.bullet != nil {
+		return *n.bullet
+	}
+	def := doc.Numbering.AddDefinition()
+	for i := 0; i < listLevels; i++ {
+		lvl := def.AddLevel()
+		if kind == ListBullet {
+			lvl.SetFormat(wml.ST_NumberFormatBullet)
+			lvl.SetText(bulletChars[i%len(bulletChars)])
+		} else {
+			lvl.SetFormat(wml.ST_NumberFormatDecimal)
+			text := ""
+			for j := 1; j <= i+1; j++ {
+				text += fmt.Sprintf("%%%d.", j)
+			}
+			lvl.SetText(text)
+		}
+		lvl.SetAlignment(wml.ST_JcLeft)
+		lvl.Properties().SetStartIndent(measurement.Distance(20*(i+1)) * measurement.Point)
+		hanging := 18
+		if kind == ListDecimal {
+			hanging += 8 * i // wider for 1.1.1.
+		}
+		lvl.Properties().SetHangingIndent(measurement.Distance(hanging) * measurement.Point)
+	}
+	if kind == ListBullet {
+		n.bullet = &def
+	}
+	return def
+}
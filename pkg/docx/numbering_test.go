@@ -0,0 +1,88 @@
+package docx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTextListItems(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []listItem
+	}{
+		{"plain", "just text", []listItem{{0, ListNone, "just text"}}},
+		{"single marker line", "- only one", []listItem{{0, ListNone, "- only one"}}},
+		{"no markers", "line 1\nline 2", []listItem{{0, ListNone, "line 1\nline 2"}}},
+		{
+			name: "bullets",
+			text: "- a\n* b\n• c",
+			want: []listItem{{0, ListBullet, "a"}, {0, ListBullet, "b"}, {0, ListBullet, "c"}},
+		},
+		{
+			name: "numbers",
+			text: "1. first\r\n2) second\r\n10. tenth",
+			want: []listItem{{0, ListDecimal, "first"}, {0, ListDecimal, "second"}, {0, ListDecimal, "tenth"}},
+		},
+		{
+			name: "nested by indent",
+			text: "1. step\n  - detail\n    - more\n  - detail 2\n2. next",
+			want: []listItem{
+				{0, ListDecimal, "step"}, {1, ListBullet, "detail"}, {2, ListBullet, "more"},
+				{1, ListBullet, "detail 2"}, {0, ListDecimal, "next"},
+			},
+		},
+		{
+			name: "continuation and text",
+			text: "Steps:\n- open\n  the file\n\n- save\nDone",
+			want: []listItem{
+				{0, ListNone, "Steps:"}, {0, ListBullet, `open\nthe file`}, {0, ListBullet, "save"}, {0, ListNone, "Done"},
+			},
+		},
+		{"not markers", "5 * 3\n-1 is negative\n1.5 ratio", []listItem{{0, ListNone, "5 * 3\n-1 is negative\n1.5 ratio"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textListItems(tt.text, 0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToListItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []listItem
+	}{
+		{"text", "a", []listItem{{0, ListNone, "a"}}},
+		{"number", 12, []listItem{{0, ListNone, "12"}}},
+		{
+			name:  "nested sequences",
+			value: []interface{}{"a", []interface{}{"b", []interface{}{"c"}}, "d"},
+			want:  []listItem{{0, ListNone, "a"}, {1, ListNone, "b"}, {2, ListNone, "c"}, {0, ListNone, "d"}},
+		},
+		{
+			name:  "markers in the sequence",
+			value: []interface{}{"intro", []interface{}{"- x\n  - y"}},
+			want:  []listItem{{0, ListNone, "intro"}, {1, ListBullet, "x"}, {2, ListBullet, "y"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toListItems(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// deeper than Word supports
+	value := interface{}("x")
+	for i := 0; i < listLevels+2; i++ {
+		value = []interface{}{value}
+	}
+	if items := toListItems(value); len(items) != 1 || items[0].level != listLevels-1 {
+		t.Errorf("got %+v, want the last level", items)
+	}
+}
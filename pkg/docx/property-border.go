@@ -1,4 +1,4 @@
-package docb
+package docx
 
 import (
 	"baliance.com/gooxml/color"
@@ -0,0 +1,26 @@
+package docx
+
+type CodeProperty struct {
+	Language    string // sql, java, javascript or go for the highlighting, plain text otherwise
+	LineNumbers bool
+	TabWidth    int // spaces of the tab
+}
+
+func newCodeProperty() *CodeProperty {
+	return &CodeProperty{TabWidth: 4}
+}
+
+func (p *CodeProperty) SetLanguage(lang string) *CodeProperty {
+	p.Language = lang
+	return p
+}
+
+func (p *CodeProperty) SetLineNumbers(show bool) *CodeProperty {
+	p.LineNumbers = show
+	return p
+}
+
+func (p *CodeProperty) SetTabWidth(width int) *CodeProperty {
+	p.TabWidth = width
+	return p
+}
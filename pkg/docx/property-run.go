@@ -0,0 +1,115 @@
+package docx
+
+import (
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/ofc/sharedTypes"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+// RunProperty overrides the font and style of the cell or paragraph for the text, the zero values keep them
+type RunProperty struct {
+	FontFamily string
+	FontSize   int
+	Bold       *bool // false turns off the bold of the cell or paragraph
+	Italic     *bool
+	Underline  wml.ST_Underline
+	Color      *color.Color
+	Highlight  wml.ST_HighlightColor
+}
+
+func newRunProperty() *RunProperty {
+	return &RunProperty{}
+}
+
+func (p *RunProperty) SetFontFamily(ff string) *RunProperty {
+	p.FontFamily = ff
+	return p
+}
+
+func (p *RunProperty) SetFontSize(fs int) *RunProperty {
+	p.FontSize = fs
+	return p
+}
+
+func (p *RunProperty) SetBold() *RunProperty {
+	p.Bold = gooxml.Bool(true)
+	return p
+}
+
+// SetNotBold turns off the bold of the cell or paragraph, e.g. the plain text in the header cell
+func (p *RunProperty) SetNotBold() *RunProperty {
+	p.Bold = gooxml.Bool(false)
+	return p
+}
+
+func (p *RunProperty) SetItalic() *RunProperty {
+	p.Italic = gooxml.Bool(true)
+	return p
+}
+
+// SetNotItalic turns off the italic of the cell or paragraph
+func (p *RunProperty) SetNotItalic() *RunProperty {
+	p.Italic = gooxml.Bool(false)
+	return p
+}
+
+func (p *RunProperty) SetUnderline(style wml.ST_Underline) *RunProperty {
+	p.Underline = style
+	return p
+}
+
+func (p *RunProperty) SetColor(c color.Color) *RunProperty {
+	p.Color = &c
+	return p
+}
+
+func (p *RunProperty) SetHighlight(h wml.ST_HighlightColor) *RunProperty {
+	p.Highlight = h
+	return p
+}
+
+// apply sets the overridden properties to the run
+func (p *RunProperty) apply(run document.Run) {
+	props := run.Properties()
+	if p.FontFamily != "" {
+		props.SetFontFamily(p.FontFamily)
+	}
+	if p.FontSize > 0 {
+		props.SetSize(measurement.Distance(p.FontSize))
+	}
+	if x := props.X(); p.Bold != nil {
+		x.B, x.BCs = onOff(*p.Bold), onOff(*p.Bold)
+	}
+	if x := props.X(); p.Italic != nil {
+		x.I, x.ICs = onOff(*p.Italic), onOff(*p.Italic)
+	}
+	if p.Color != nil {
+		props.SetColor(*p.Color)
+	}
+	if p.Underline != wml.ST_UnderlineUnset {
+		props.SetUnderline(p.Underline, xcolor(p.Color))
+	}
+	if p.Highlight != wml.ST_HighlightColorUnset {
+		props.SetHighlight(p.Highlight)
+	}
+}
+
+// onOff returns the toggle of the run, the false one is written to override the style, e.g. <w:b w:val="false"/>
+func onOff(on bool) *wml.CT_OnOff {
+	v := wml.NewCT_OnOff()
+	if !on {
+		v.ValAttr = &sharedTypes.ST_OnOff{Bool: gooxml.Bool(false)}
+	}
+	return v
+}
+
+// xcolor returns the color, or the automatic color if it is not set, e.g. the color of the underline
+func xcolor(c *color.Color) color.Color {
+	if c != nil {
+		return *c
+	}
+	return color.Auto
+}
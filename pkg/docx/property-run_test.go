@@ -0,0 +1,57 @@
+package docx
+
+import (
+	"testing"
+
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+func TestRunPropertyApply(t *testing.T) {
+	tests := []struct {
+		name         string
+		set          func(*RunProperty)
+		bold, italic string
+	}{
+		{"unset", func(*RunProperty) {}, "unset", "unset"},
+		{"bold", func(p *RunProperty) { p.SetBold() }, "true", "unset"},
+		{"not bold", func(p *RunProperty) { p.SetNotBold() }, "false", "unset"},
+		{"italic", func(p *RunProperty) { p.SetItalic() }, "unset", "true"},
+		{"not italic", func(p *RunProperty) { p.SetNotItalic() }, "unset", "false"},
+		{"bold not italic", func(p *RunProperty) { p.SetBold().SetNotItalic() }, "true", "false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := document.New().AddParagraph().AddRun()
+			p := newRunProperty()
+			tt.set(p)
+			p.apply(run)
+			x := run.X().RPr
+			if x == nil {
+				x = wml.NewCT_RPr()
+			}
+			assertOnOff(t, "b", x.B, tt.bold)
+			assertOnOff(t, "bCs", x.BCs, tt.bold)
+			assertOnOff(t, "i", x.I, tt.italic)
+			assertOnOff(t, "iCs", x.ICs, tt.italic)
+		})
+	}
+}
+
+// assertOnOff checks the toggle written, unset if the element is absent, otherwise the w:val
+func assertOnOff(t *testing.T, name string, v *wml.CT_OnOff, want string) {
+	t.Helper()
+	got := "unset"
+	switch {
+	case v == nil:
+	case v.ValAttr == nil || v.ValAttr.Bool == nil:
+		got = "true"
+	case *v.ValAttr.Bool:
+		got = "true"
+	default:
+		got = "false"
+	}
+	if got != want {
+		t.Errorf("w:%s got %s, want %s", name, got, want)
+	}
+}
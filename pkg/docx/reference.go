@@ -0,0 +1,80 @@
+package docx
+
+import (
+	"fmt"
+	"regexp"
+
+	"baliance.com/gooxml"
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+// ReferenceField is the field of the cross-reference to the bookmark, updated by Word
+type ReferenceField string
+
+const (
+	RefText ReferenceField = "REF"     // text of the bookmark
+	RefPage ReferenceField = "PAGEREF" // page number of the bookmark
+)
+
+// url in the text which is linked without the [text](url) format
+var bareURL = regexp.MustCompile(`\bhttps?://[^\s<>()]*[^\s<>().,;:!?'"]`)
+
+// inlineContent is the hyperlink, the cross-reference or the styled text added after the text of the paragraph
+type inlineContent struct {
+	text     string
+	url      string // external hyperlink
+	bookmark string // internal hyperlink or the target of the field
+	field    ReferenceField
+	style    *RunProperty // plain text with the style
+}
+
+// add adds the runs of the content to the paragraph, format sets the font of the runs
+func (ic inlineContent) add(p document.Paragraph, format func(document.Run)) {
+	if ic.style != nil {
+		run := p.AddRun()
+		format(run)
+		ic.style.apply(run)
+		run.AddText(ic.text)
+		return
+	}
+	if ic.field != "" {
+		run := p.AddRun()
+		format(run)
+		run.AddFieldWithFormatting(fmt.Sprintf(`%s %s \h`, ic.field, ic.bookmark), "", true)
+		return
+	}
+	link := p.AddHyperLink()
+	if ic.url != "" {
+		link.SetTarget(ic.url)
+	} else {
+		link.X().AnchorAttr = gooxml.String(ic.bookmark)
+	}
+	run := link.AddRun()
+	format(run)
+	setLinkStyle(run)
+	run.AddText(ic.text)
+}
+
+func setLinkStyle(run document.Run) {
+	run.Properties().SetColor(color.Blue)
+	run.Properties().SetUnderline(wml.ST_UnderlineSingle, color.Blue)
+}
+
+// urlSegments splits the text into the plain parts and the bare urls
+func urlSegments(text string) []inlineSpan {
+	spans := []inlineSpan{}
+	last := 0
+	for _, loc := range bareURL.FindAllStringIndex(text, -1) {
+		if loc[0] > last {
+			spans = append(spans, inlineSpan{text: text[last:loc[0]]})
+		}
+		spans = append(spans, inlineSpan{text: text[loc[0]:loc[1]], style: inlineStyle{link: text[loc[0]:loc[1]]}})
+		last = loc[1]
+	}
+	if last < len(text) || len(spans) == 0 {
+		spans = append(spans, inlineSpan{text: text[last:]})
+	}
+	return spans
+}
@@ -0,0 +1,109 @@
+package docx
+
+import (
+	"strings"
+
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/measurement"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+// StyleProperty is the default of the paragraph style created if the document doesn't have it
+type StyleProperty struct {
+	FontFamily string // font of the configuration by default
+	FontSize   int    // size of the configuration by default
+	Bold       bool
+	Italic     bool
+	Color      *color.Color
+	Shading    *color.Color // background of the paragraph and the table cell having the style
+}
+
+func (p *StyleProperty) SetFontFamily(ff string) *StyleProperty {
+	p.FontFamily = ff
+	return p
+}
+
+func (p *StyleProperty) SetFontSize(fs int) *StyleProperty {
+	p.FontSize = fs
+	return p
+}
+
+func (p *StyleProperty) SetBold() *StyleProperty {
+	p.Bold = true
+	return p
+}
+
+func (p *StyleProperty) SetItalic() *StyleProperty {
+	p.Italic = true
+	return p
+}
+
+func (p *StyleProperty) SetColor(c color.Color) *StyleProperty {
+	p.Color = &c
+	return p
+}
+
+func (p *StyleProperty) SetShading(c color.Color) *StyleProperty {
+	p.Shading = &c
+	return p
+}
+
+// AddStyle returns the ID of the paragraph style by the name or ID. The style of the document, e.g. from the
+// template, is used as it is, otherwise the style is created by set.
+func (d *DocumentBuilder) AddStyle(name string, set func(*StyleProperty)) string {
+	for _, s := range d.Document.Styles.Styles() {
+		if s.Type() == wml.ST_StyleTypeParagraph && (s.Name() == name || s.StyleID() == name) {
+			if shading, ok := styleShading(s); ok {
+				d.config.shadings[s.StyleID()] = shading
+			}
+			return s.StyleID()
+		}
+	}
+
+	sp := &StyleProperty{FontFamily: d.config.FontFamily, FontSize: d.config.FontSize}
+	set(sp)
+	id := strings.ReplaceAll(name, " ", "")
+	s := d.Document.Styles.AddStyle(id, wml.ST_StyleTypeParagraph, false)
+	s.SetName(name)
+	s.SetBasedOn("Normal")
+	s.SetPrimaryStyle(true)
+	rp := s.RunProperties()
+	rp.SetFontFamily(sp.FontFamily)
+	rp.SetSize(measurement.Distance(sp.FontSize))
+	rp.SetBold(sp.Bold)
+	rp.SetItalic(sp.Italic)
+	if sp.Color != nil {
+		rp.SetColor(*sp.Color)
+	}
+	if sp.Shading != nil {
+		x := s.X()
+		if x.PPr == nil {
+			x.PPr = wml.NewCT_PPrGeneral()
+		}
+		x.PPr.Shd = wml.NewCT_Shd()
+		x.PPr.Shd.ValAttr = wml.ST_ShdClear
+		x.PPr.Shd.ColorAttr = &wml.ST_HexColor{ST_HexColorAuto: wml.ST_HexColorAutoAuto}
+		x.PPr.Shd.FillAttr = &wml.ST_HexColor{ST_HexColorRGB: sp.Shading.AsRGBString()}
+		d.config.shadings[id] = *sp.Shading
+	}
+	return id
+}
+
+// styleShading returns the color of the shading of the paragraph style, the cells having the style are
+// filled with it
+func styleShading(s document.Style) (color.Color, bool) {
+	x := s.X()
+	if x == nil || x.PPr == nil || x.PPr.Shd == nil {
+		return color.Color{}, false
+	}
+	hex := x.PPr.Shd.FillAttr
+	if x.PPr.Shd.ValAttr == wml.ST_ShdSolid {
+		// the pattern covers the fill
+		hex = x.PPr.Shd.ColorAttr
+	}
+	if hex == nil || hex.ST_HexColorRGB == nil {
+		return color.Color{}, false
+	}
+	return color.FromHex(*hex.ST_HexColorRGB), true
+}
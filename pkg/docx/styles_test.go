@@ -0,0 +1,121 @@
+package docx
+
+import (
+	"testing"
+
+	"baliance.com/gooxml/color"
+	"baliance.com/gooxml/document"
+	"baliance.com/gooxml/schema/soo/wml"
+)
+
+func TestAddStyle(t *testing.T) {
+	d, err := NewDocumentBuilder("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the style of the template, the solid pattern covers the fill
+	template := d.Document.Styles.AddStyle("Solid", wml.ST_StyleTypeParagraph, false)
+	template.SetName("Solid Header")
+	template.X().PPr = wml.NewCT_PPrGeneral()
+	template.X().PPr.Shd = newShading(wml.ST_ShdSolid, hexColor("1F4E79"), hexColor("FFFFFF"))
+
+	for _, name := range []string{"Solid Header", "Solid"} {
+		if id := d.AddStyle(name, func(*StyleProperty) { t.Errorf("%s is created again", name) }); id != "Solid" {
+			t.Errorf("got %s for %s, want Solid", id, name)
+		}
+	}
+	assertShading(t, d.config.shadings, "Solid", "1F4E79")
+
+	id := d.AddStyle("Table Header", func(p *StyleProperty) { p.SetBold().SetShading(color.FromHex("D9D9D9")) })
+	if id != "TableHeader" {
+		t.Fatalf("got %s, want TableHeader", id)
+	}
+	assertShading(t, d.config.shadings, "TableHeader", "D9D9D9")
+	created := findStyle(d.Document, id)
+	if created == nil || created.Name() != "Table Header" {
+		t.Fatalf("style %s not created", id)
+	}
+	x := created.X()
+	if x.RPr == nil || x.RPr.B == nil {
+		t.Error("bold not written")
+	}
+	if x.PPr == nil || x.PPr.Shd == nil || x.PPr.Shd.ValAttr != wml.ST_ShdClear ||
+		x.PPr.Shd.FillAttr == nil || !sameHex(x.PPr.Shd.FillAttr.ST_HexColorRGB, "D9D9D9") {
+		t.Errorf("got shading %+v, want clear with fill D9D9D9", x.PPr)
+	}
+
+	// created once and reused
+	styles := len(d.Document.Styles.Styles())
+	if id := d.AddStyle("Table Header", func(*StyleProperty) { t.Error("Table Header is created again") }); id != "TableHeader" {
+		t.Errorf("got %s, want TableHeader", id)
+	}
+	if n := len(d.Document.Styles.Styles()); n != styles {
+		t.Errorf("got %d styles, want %d", n, styles)
+	}
+
+	d.AddStyle("Plain", func(*StyleProperty) {})
+	if _, ok := d.config.shadings["Plain"]; ok {
+		t.Error("got shading of Plain, want none")
+	}
+}
+
+func TestStyleShading(t *testing.T) {
+	tests := []struct {
+		name string
+		shd  *wml.CT_Shd
+		want string // hex of the color, or blank if none
+	}{
+		{"no shading", nil, ""},
+		{"clear", newShading(wml.ST_ShdClear, &wml.ST_HexColor{ST_HexColorAuto: wml.ST_HexColorAutoAuto}, hexColor("D9D9D9")), "D9D9D9"},
+		{"solid", newShading(wml.ST_ShdSolid, hexColor("1F4E79"), hexColor("FFFFFF")), "1F4E79"},
+		{"solid auto", newShading(wml.ST_ShdSolid, &wml.ST_HexColor{ST_HexColorAuto: wml.ST_HexColorAutoAuto}, hexColor("FFFFFF")), ""},
+		{"clear auto", newShading(wml.ST_ShdClear, nil, &wml.ST_HexColor{ST_HexColorAuto: wml.ST_HexColorAutoAuto}), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := document.New().Styles.AddStyle("Test", wml.ST_StyleTypeParagraph, false)
+			if tt.shd != nil {
+				s.X().PPr = wml.NewCT_PPrGeneral()
+				s.X().PPr.Shd = tt.shd
+			}
+			got, ok := styleShading(s)
+			if ok != (tt.want != "") || ok && !sameHex(got.AsRGBString(), tt.want) {
+				t.Errorf("got %v, %v, want %s", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func newShading(val wml.ST_Shd, c, fill *wml.ST_HexColor) *wml.CT_Shd {
+	shd := wml.NewCT_Shd()
+	shd.ValAttr = val
+	shd.ColorAttr = c
+	shd.FillAttr = fill
+	return shd
+}
+
+func hexColor(hex string) *wml.ST_HexColor {
+	return &wml.ST_HexColor{ST_HexColorRGB: &hex}
+}
+
+func findStyle(doc *document.Document, id string) *document.Style {
+	for _, s := range doc.Styles.Styles() {
+		if s.StyleID() == id {
+			return &s
+		}
+	}
+	return nil
+}
+
+func assertShading(t *testing.T, shadings map[string]color.Color, id, want string) {
+	t.Helper()
+	got, ok := shadings[id]
+	if !ok || !sameHex(got.AsRGBString(), want) {
+		t.Errorf("got shading %v, %v of %s, want %s", got, ok, id, want)
+	}
+}
+
+// sameHex compares the colors in hex ignoring the case
+func sameHex(hex *string, want string) bool {
+	return hex != nil && color.FromHex(*hex) == color.FromHex(want)
+}
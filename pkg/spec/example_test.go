@@ -0,0 +1,60 @@
+package spec_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/zrs01/pst/pkg/spec"
+)
+
+// writeSpec writes the spec of the examples into a temporary directory, returns the file
+func writeSpec() (string, func()) {
+	dir, err := os.MkdirTemp("", "spec")
+	if err != nil {
+		panic(err)
+	}
+	file := filepath.Join(dir, "spec.yml")
+	content := `modules:
+  - name: User
+    features:
+      - id: UF010A
+        name: Register
+        tests:
+          - { desc: "New registration submit", expect: "Account created" }
+      - id: UF011A
+        name: Login
+`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		panic(err)
+	}
+	return file, func() { os.RemoveAll(dir) }
+}
+
+func ExampleLoad() {
+	file, clean := writeSpec()
+	defer clean()
+
+	specs, err := spec.Load("", file)
+	if err != nil {
+		panic(err)
+	}
+	for _, s := range specs {
+		for _, module := range s.Data.Modules {
+			fmt.Println(module.Name, len(module.Features))
+		}
+	}
+	// Output: User 2
+}
+
+func ExampleBuild() {
+	file, clean := writeSpec()
+	defer clean()
+
+	// blank config and template files use the defaults
+	err := spec.Build("", file, filepath.Join(filepath.Dir(file), "spec.docx"), "", spec.Options{
+		Vars: map[string]string{"version": "1.2"},
+	})
+	fmt.Println(err)
+	// Output: <nil>
+}
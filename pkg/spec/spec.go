@@ -0,0 +1,91 @@
+/*
+Package spec loads and renders the program specifications (ProgSpec) of pst, e.g. to embed pst in other
+services. The arguments are the same as the pst command line: the config file, the input file or glob,
+the output file (.docx, or .xlsx for the test records) and the .docx template. Blank config or template
+files use the defaults.
+
+	err := spec.Build("pst.yml", "specs/*.yml", "spec.docx", "template.docx", spec.Options{
+		Vars: map[string]string{"version": "1.2"},
+	})
+
+	specs, err := spec.Load("", "specs/*.yml")
+	for _, s := range specs {
+		for _, module := range s.Data.Modules {
+			fmt.Println(module.Name, len(module.Features))
+		}
+	}
+
+The content can be provided without files by Options.Overlay, keyed by the file name of the input.
+
+The types of the package are the aliases of the types used by pst internally, e.g. spec.Feature is the
+same type as the feature built into the document, so the values are passed between the functions without
+conversion. Their exported fields and methods are part of the API of this package.
+
+The package follows the semantic versioning of the pst releases, the exported API is not changed
+incompatibly within the same major version.
+*/
+package spec
+
+import "github.com/zrs01/pst/internal/docb"
+
+// model of the input file, the aliases of the internal types
+type (
+	ProgSpec  = docb.ProgSpec
+	Module    = docb.Module
+	Feature   = docb.Feature
+	Api       = docb.Api
+	Env       = docb.Env
+	Resource  = docb.Resource
+	Screen    = docb.Screen
+	Input     = docb.Input
+	Parameter = docb.Parameter
+	Scenario  = docb.Scenario
+	Image     = docb.Image
+	Others    = docb.Others
+	Test      = docb.Test
+	Code      = docb.Code
+	Position  = docb.Position
+)
+
+// Options are the optional settings of Build and Load
+type Options = docb.Options
+
+// Spec is the loaded input file with the variables substituted and the image paths resolved
+type Spec = docb.Spec
+
+// ModuleStats is the statistics of the module, see Stats
+type ModuleStats = docb.ModuleStats
+
+// StdStream is the file name of stdin for input and stdout for output
+const StdStream = docb.StdStream
+
+// Build writes the document of the input files, ofile is the output directory if Options.Split is set
+func Build(cfile, ifile, ofile, tfile string, opts ...Options) error {
+	return docb.Build(cfile, ifile, ofile, tfile, opts...)
+}
+
+// Load loads the input files and appendices with the same arguments as Build without writing the document
+func Load(cfile, ifile string, opts ...Options) ([]Spec, error) {
+	return docb.Load(cfile, ifile, opts...)
+}
+
+// Dependencies returns the files read by the build, e.g. to watch the changes
+func Dependencies(cfile, ifile, tfile string, opts ...Options) ([]string, error) {
+	return docb.Dependencies(cfile, ifile, tfile, opts...)
+}
+
+// Stats counts the features of the specs by module
+func Stats(specs []*ProgSpec) []ModuleStats {
+	return docb.Stats(specs)
+}
+
+// TotalStats sums up the statistics of the modules
+func TotalStats(stats []ModuleStats) ModuleStats {
+	return docb.TotalStats(stats)
+}
+
+// ImportActualResults updates the actual results of the tests in the input files by the .xlsx test
+// records, returns the files changed
+func ImportActualResults(xfile string, specs []Spec) ([]string, error) {
+	return docb.ImportActualResults(xfile, specs)
+}